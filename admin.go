@@ -0,0 +1,251 @@
+package unixcycle
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// adminCommandTimeout bounds how long a client has to send its command and health checks may take
+const adminCommandTimeout = 10 * time.Second
+
+var _ Component = &adminSocketComponent{}
+
+// adminSocketComponent serves operator commands for a manager on a unix socket
+type adminSocketComponent struct {
+	boundListener
+	manager *Manager
+	path    string
+
+	wg sync.WaitGroup
+}
+
+// AdminSocket creates a component accepting one command per connection on the unix socket at path, e.g. with
+//
+//	echo status | nc -U /var/run/app.sock
+//
+// Commands are:
+//   - status: the Report of the manager
+//   - health: the Health of every component
+//   - shutdown: shut the manager down as if SIGTERM was received
+//   - restart <component>: restart a single component, see Manager.Restart
+//   - loglevel <level>: set the level of the default logger, e.g. debug, see Manager.SetLogLevel
+//
+// Anyone who can connect to the socket can shut down the service, so restrict access with the permissions of its directory
+func (m *Manager) AdminSocket(path string) *adminSocketComponent {
+	return &adminSocketComponent{manager: m, path: path}
+}
+
+// Setup listens on the socket, removing a stale socket file left behind by a crashed process
+func (a *adminSocketComponent) Setup() error {
+	if a.inherit("unix", a.path) {
+		return nil // Still served by the previous process of an upgrade until it shuts down
+	}
+	if conn, err := net.Dial("unix", a.path); err == nil {
+		conn.Close()
+		return fmt.Errorf("admin socket %q is in use by another process", a.path)
+	}
+	if info, err := os.Lstat(a.path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(a.path); err != nil {
+			return fmt.Errorf("removing stale admin socket: %w", err)
+		}
+	}
+
+	return a.listen("unix", a.path)
+}
+
+func (a *adminSocketComponent) Start() error {
+	defer a.wg.Wait()
+	for {
+		conn, err := a.bound().Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			a.serve(conn)
+		}()
+	}
+}
+
+// Close closes the listener, which removes the socket file
+func (a *adminSocketComponent) Close() error {
+	err := a.bound().Close()
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+func (a *adminSocketComponent) serve(conn net.Conn) {
+	defer conn.Close()
+
+	_ = conn.SetReadDeadline(time.Now().Add(adminCommandTimeout))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && line == "" {
+		return
+	}
+
+	_, _ = conn.Write([]byte(a.run(strings.Fields(line))))
+}
+
+// run executes a command, returning the response to send back
+func (a *adminSocketComponent) run(args []string) string {
+	if len(args) == 0 {
+		return "error: no command, expected one of status, health, shutdown, restart <component>, loglevel <level>\n"
+	}
+	a.manager.logInfo(CodeAdminCommand, fmt.Sprintf("Received admin command %q", strings.Join(args, " ")), slog.String("command", args[0]))
+
+	switch {
+	case args[0] == "status" && len(args) == 1:
+		return a.manager.Report()
+	case args[0] == "health" && len(args) == 1:
+		ctx, cancel := context.WithTimeout(context.Background(), adminCommandTimeout)
+		defer cancel()
+		return healthText(a.manager.Health(ctx))
+	case args[0] == "shutdown" && len(args) == 1:
+		a.manager.signal(int(syscall.SIGTERM))
+		return "ok: shutting down\n"
+	case args[0] == "restart" && len(args) == 2:
+		if err := a.manager.Restart(args[1]); err != nil {
+			return fmt.Sprintf("error: %v\n", err)
+		}
+		return fmt.Sprintf("ok: restarted %s\n", args[1])
+	case args[0] == "loglevel" && len(args) == 2:
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(args[1])); err != nil {
+			return fmt.Sprintf("error: %v\n", err)
+		}
+		if err := a.manager.SetLogLevel(level); err != nil {
+			return fmt.Sprintf("error: %v\n", err)
+		}
+		return fmt.Sprintf("ok: log level is %s\n", level)
+	default:
+		return fmt.Sprintf("error: unknown command %q, expected one of status, health, shutdown, restart <component>, loglevel <level>\n", strings.Join(args, " "))
+	}
+}
+
+// healthText renders a health report one component per line, after whether all of them are healthy
+func healthText(report HealthReport) string {
+	var b strings.Builder
+	if report.Healthy {
+		b.WriteString("healthy\n")
+	} else {
+		b.WriteString("unhealthy\n")
+	}
+	for _, c := range report.Components {
+		if c.Err != nil {
+			fmt.Fprintf(&b, "%s: %v\n", c.Name, c.Err)
+		} else {
+			fmt.Fprintf(&b, "%s: ok\n", c.Name)
+		}
+	}
+	return b.String()
+}
+
+// adminComponent is the JSON representation of a component served by AdminHandler
+type adminComponent struct {
+	Name       string         `json:"name"`
+	State      ComponentState `json:"state"`
+	Since      time.Time      `json:"since"`
+	Interfaces []string       `json:"interfaces"`
+	Tags       []string       `json:"tags,omitempty"`
+	Durations  adminDurations `json:"durations"`
+	LastError  string         `json:"last_error,omitempty"`
+}
+
+// adminDurations are the ComponentTimings formatted like time.Duration.String, omitted when zero
+type adminDurations struct {
+	Setup      string `json:"setup,omitempty"`
+	Drain      string `json:"drain,omitempty"`
+	Checkpoint string `json:"checkpoint,omitempty"`
+	Close      string `json:"close,omitempty"`
+	Uptime     string `json:"uptime,omitempty"`
+}
+
+// AdminHandler returns a handler for admin tooling, built on Components:
+//   - GET /components lists the components as JSON, with their states, interfaces, tags, durations and last errors
+//   - POST /shutdown shuts the manager down as if SIGTERM was received
+//
+// Requests are rejected with 401 unless authorize returns true, e.g. after checking a bearer token. A nil authorize allows all
+// requests, so only do that on an address that is not publicly reachable. See AdminServer to serve it on its own address
+func (m *Manager) AdminHandler(authorize func(r *http.Request) bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /components", func(w http.ResponseWriter, r *http.Request) {
+		components := []adminComponent{}
+		for info := range m.Components() {
+			components = append(components, newAdminComponent(info))
+		}
+		writeJSON(w, http.StatusOK, components)
+	})
+	mux.HandleFunc("POST /shutdown", func(w http.ResponseWriter, r *http.Request) {
+		m.logInfo(CodeAdminCommand, "Received admin request to shut down", slog.String("command", "shutdown"))
+		m.signal(int(syscall.SIGTERM))
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "shutting down"})
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authorize != nil && !authorize(r) {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// AdminServer creates a component serving AdminHandler on addr, see HTTPServer
+func (m *Manager) AdminServer(addr string, authorize func(r *http.Request) bool) *httpServerComponent {
+	return HTTPServer(&http.Server{Addr: addr, Handler: m.AdminHandler(authorize), ReadHeaderTimeout: adminCommandTimeout})
+}
+
+func newAdminComponent(info ComponentInfo) adminComponent {
+	c := adminComponent{
+		Name:       info.Name,
+		State:      info.State,
+		Interfaces: info.Interfaces,
+		Tags:       info.Tags,
+		Durations: adminDurations{
+			Setup:      formatDuration(info.Timings.Setup),
+			Drain:      formatDuration(info.Timings.Drain),
+			Checkpoint: formatDuration(info.Timings.Checkpoint),
+			Close:      formatDuration(info.Timings.Close),
+			Uptime:     formatDuration(info.Timings.Uptime),
+		},
+	}
+	if len(info.Transitions) > 0 {
+		c.Since = info.Transitions[len(info.Transitions)-1].Time
+	}
+	if info.LastError != nil {
+		c.LastError = info.LastError.Error()
+	}
+	return c
+}
+
+// formatDuration is empty for zero durations, so they are omitted from JSON
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
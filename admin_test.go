@@ -0,0 +1,183 @@
+package unixcycle_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestAdminSocket(t *testing.T) {
+	send := func(t *testing.T, path, command string) string {
+		conn, err := net.Dial("unix", path)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = io.WriteString(conn, command+"\n")
+		require.NoError(t, err)
+		response, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		return string(response)
+	}
+
+	t.Run("should answer operator commands while running", func(t *testing.T) {
+		var (
+			path     = filepath.Join(t.TempDir(), "admin.sock")
+			db       = &restartableComponent{}
+			shutdown = make(chan int, 1)
+			sut      = unixcycle.NewManager(unixcycle.WithLifetime(func() int { return <-shutdown }))
+			done     = make(chan int)
+		)
+		sut.
+			Add("worker", unixcycle.Runner(func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() })).
+			Add("db", db).
+			Add("admin", sut.AdminSocket(path))
+		go func() { done <- sut.Run() }()
+		<-sut.Started()
+
+		assert.Contains(t, send(t, path, "status"), "worker")
+		assert.Equal(t, "healthy\nworker: ok\ndb: ok\nadmin: ok\n", send(t, path, "health"))
+		assert.Equal(t, "ok: log level is DEBUG\n", send(t, path, "loglevel debug"))
+		assert.Contains(t, send(t, path, "loglevel loud"), "error: ")
+		assert.Contains(t, send(t, path, "restart nope"), `error: unknown component "nope"`)
+		assert.Contains(t, send(t, path, "fly"), `error: unknown command "fly"`)
+
+		assert.Equal(t, "ok: restarted db\n", send(t, path, "restart db"))
+		assert.Equal(t, int32(2), db.setups.Load())
+
+		assert.Equal(t, "ok: shutting down\n", send(t, path, "shutdown"))
+		assert.Equal(t, int(syscall.SIGTERM), <-done)
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("should replace a stale socket file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "admin.sock")
+		listener, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		listener.(*net.UnixListener).SetUnlinkOnClose(false)
+		require.NoError(t, listener.Close())
+		sut := unixcycle.NewManager(unixcycle.WithLifetime(func() int { time.Sleep(10 * time.Millisecond); return 0 }))
+		sut.Add("admin", sut.AdminSocket(path))
+
+		got := sut.RunWithResult()
+
+		assert.NoError(t, got.Err)
+	})
+
+	t.Run("should fail the setup when the socket is in use", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "admin.sock")
+		listener, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		defer listener.Close()
+		sut := unixcycle.NewManager(unixcycle.WithLifetime(func() int { return 0 }))
+		sut.Add("admin", sut.AdminSocket(path))
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, unixcycle.PhaseSetup, got.FailedPhase)
+		assert.ErrorContains(t, got.Err, "in use by another process")
+	})
+}
+
+func TestAdminHandler(t *testing.T) {
+	t.Run("should list components as JSON", func(t *testing.T) {
+		sut := unixcycle.NewManager(unixcycle.WithLifetime(func() int { return 0 })).
+			Add("broken closer", unixcycle.Closer(func() error { return assert.AnError }), unixcycle.Tags("db"))
+		sut.Run()
+		rec := httptest.NewRecorder()
+
+		sut.AdminHandler(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/components", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		var got []map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		require.Len(t, got, 1)
+		assert.NotEmpty(t, got[0]["since"])
+		assert.Contains(t, got[0]["durations"], "uptime")
+		delete(got[0], "since")
+		delete(got[0], "durations")
+		assert.Equal(t, map[string]any{
+			"name":       "broken closer",
+			"state":      "failed",
+			"interfaces": []any{"Start", "Close"},
+			"tags":       []any{"db"},
+			"last_error": assert.AnError.Error(),
+		}, got[0])
+	})
+
+	t.Run("should shut down on POST /shutdown", func(t *testing.T) {
+		var (
+			sut  = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} }))
+			done = make(chan int)
+		)
+		go func() { done <- sut.Run() }()
+		<-sut.Started()
+		rec := httptest.NewRecorder()
+
+		sut.AdminHandler(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/shutdown", nil))
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, int(syscall.SIGTERM), <-done)
+	})
+
+	t.Run("should reject unauthorized requests and unknown methods", func(t *testing.T) {
+		sut := unixcycle.NewManager().AdminHandler(func(r *http.Request) bool { return r.Header.Get("Authorization") == "Bearer secret" })
+
+		unauthorized := httptest.NewRecorder()
+		sut.ServeHTTP(unauthorized, httptest.NewRequest(http.MethodPost, "/shutdown", nil))
+		wrongMethod := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/shutdown", nil)
+		request.Header.Set("Authorization", "Bearer secret")
+		sut.ServeHTTP(wrongMethod, request)
+
+		assert.Equal(t, http.StatusUnauthorized, unauthorized.Code)
+		assert.Equal(t, http.StatusMethodNotAllowed, wrongMethod.Code)
+	})
+}
+
+// restartableComponent runs until closed, and can be set up and started again after that
+type restartableComponent struct {
+	setups atomic.Int32
+
+	mu   sync.Mutex
+	stop chan struct{}
+}
+
+func (c *restartableComponent) Setup() error {
+	c.setups.Add(1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stop = make(chan struct{})
+	return nil
+}
+
+func (c *restartableComponent) Start() error {
+	c.mu.Lock()
+	stop := c.stop
+	c.mu.Unlock()
+
+	<-stop
+	return nil
+}
+
+func (c *restartableComponent) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	close(c.stop)
+	return nil
+}
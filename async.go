@@ -0,0 +1,50 @@
+package unixcycle
+
+import "context"
+
+// RunHandle controls a manager running in the background, see Manager.RunAsync
+type RunHandle struct {
+	manager *Manager
+	done    chan struct{}
+	result  Result
+}
+
+// RunAsync runs the manager in the background and returns immediately.
+// Use the handle to wait for the result or to make the manager shut down
+func (m *Manager) RunAsync() *RunHandle {
+	return m.RunAsyncContext(context.Background())
+}
+
+// RunAsyncContext is like RunAsync, but the manager also shuts down when ctx is done, see RunContext
+func (m *Manager) RunAsyncContext(ctx context.Context) *RunHandle {
+	h := &RunHandle{
+		manager: m,
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		h.result = m.runWithResult(ctx)
+		close(h.done)
+	}()
+
+	return h
+}
+
+// Done returns a channel receiving the Result once the manager has shut down.
+// Every call returns a new channel, so any number of goroutines can wait for the result
+func (h *RunHandle) Done() <-chan Result {
+	results := make(chan Result, 1)
+	go func() {
+		<-h.done
+		results <- h.result
+		close(results)
+	}()
+
+	return results
+}
+
+// Signal makes the manager shut down as if its lifetime returned sig.
+// Only the first signal has an effect
+func (h *RunHandle) Signal(sig int) {
+	h.manager.signal(sig)
+}
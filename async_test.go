@@ -0,0 +1,32 @@
+package unixcycle_test
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestRunAsync(t *testing.T) {
+	t.Run("should shut down on signal and deliver the result to every waiter", func(t *testing.T) {
+		var (
+			closed = make(chan struct{})
+			sut    = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).
+				Add("closable func", unixcycle.Closer(func() error { close(closed); return nil }))
+		)
+
+		handle := sut.RunAsync()
+		first, second := handle.Done(), handle.Done()
+		handle.Signal(int(syscall.SIGTERM))
+
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			t.Fatal("manager should have shut down")
+		}
+		assert.Equal(t, int(syscall.SIGTERM), (<-first).Signal)
+		assert.Equal(t, int(syscall.SIGTERM), (<-second).Signal)
+	})
+}
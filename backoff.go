@@ -0,0 +1,49 @@
+package unixcycle
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff returns the delay to wait before the given retry attempt, where the first retry is attempt 1.
+// Backoffs are composable values, so the same policy can be shared by supervised restarts (WithSupervision) and BackoffProber
+type Backoff func(attempt int) time.Duration
+
+// ConstantBackoff waits the same delay before every attempt
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff waits initial before the first attempt and multiplies the delay by multiplier for every attempt after that
+func ExponentialBackoff(initial time.Duration, multiplier float64) Backoff {
+	return func(attempt int) time.Duration {
+		delay := float64(initial) * math.Pow(multiplier, float64(max(attempt-1, 0)))
+		if delay > math.MaxInt64 {
+			return time.Duration(math.MaxInt64)
+		}
+		return time.Duration(delay)
+	}
+}
+
+// WithCap limits the delay of b to at most maxDelay
+func (b Backoff) WithCap(maxDelay time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		return min(b(attempt), maxDelay)
+	}
+}
+
+// WithJitter randomizes the delay of b by up to +/- fraction of the delay, e.g. 0.2 for +/- 20%.
+// Jitter keeps many retrying clients from hammering a dependency in lockstep
+func (b Backoff) WithJitter(fraction float64) Backoff {
+	return func(attempt int) time.Duration {
+		delay := float64(b(attempt))
+		jitter := delay * fraction * (2*rand.Float64() - 1)
+		if delay+jitter >= math.MaxInt64 {
+			return time.Duration(math.MaxInt64) // Don't wrap around to a negative delay
+		}
+		return time.Duration(max(delay+jitter, 0))
+	}
+}
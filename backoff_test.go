@@ -0,0 +1,50 @@
+package unixcycle_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Run("ConstantBackoff should return the same delay for every attempt", func(t *testing.T) {
+		sut := unixcycle.ConstantBackoff(time.Second)
+
+		assert.Equal(t, time.Second, sut(1))
+		assert.Equal(t, time.Second, sut(10))
+	})
+
+	t.Run("ExponentialBackoff should multiply the delay for every attempt", func(t *testing.T) {
+		sut := unixcycle.ExponentialBackoff(100*time.Millisecond, 2)
+
+		assert.Equal(t, 100*time.Millisecond, sut(1))
+		assert.Equal(t, 200*time.Millisecond, sut(2))
+		assert.Equal(t, 800*time.Millisecond, sut(4))
+	})
+
+	t.Run("WithCap should limit the delay", func(t *testing.T) {
+		sut := unixcycle.ExponentialBackoff(100*time.Millisecond, 2).WithCap(time.Second)
+
+		assert.Equal(t, 800*time.Millisecond, sut(4))
+		assert.Equal(t, time.Second, sut(5))
+		assert.Equal(t, time.Second, sut(100))
+	})
+
+	t.Run("WithJitter should keep the delay within the fraction", func(t *testing.T) {
+		sut := unixcycle.ConstantBackoff(time.Second).WithJitter(0.1)
+
+		for attempt := range 100 {
+			assert.InDelta(t, time.Second, sut(attempt), float64(100*time.Millisecond))
+		}
+	})
+
+	t.Run("WithJitter should not overflow a delay close to the maximum", func(t *testing.T) {
+		sut := unixcycle.ExponentialBackoff(time.Second, 10).WithJitter(0.5)
+
+		for range 100 {
+			assert.Positive(t, sut(30))
+		}
+	})
+}
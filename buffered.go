@@ -0,0 +1,176 @@
+package unixcycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var _ Component = &bufferedWriterComponent[any]{}
+
+// BufferedWriterStats reports what happened to the records enqueued on a buffered writer
+type BufferedWriterStats struct {
+	Flushed int
+	Dropped int
+}
+
+// bufferedWriterComponent batches records enqueued while running and flushes them periodically,
+// when the buffer is full, and a final time on Close
+type bufferedWriterComponent[T any] struct {
+	flush         func(ctx context.Context, batch []T) error
+	capacity      int
+	interval      time.Duration
+	closeDeadline time.Duration
+
+	mu      sync.Mutex
+	buffer  []T
+	closed  bool
+	started bool
+	stats   BufferedWriterStats
+	cancel  context.CancelFunc
+
+	full chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// BufferedWriter creates a component for buffered sinks like batching exporters and log shippers.
+// Records are enqueued with Enqueue and handed to flush in batches every interval, or as soon as capacity is reached.
+// Records enqueued while the buffer is full are dropped. On Close the remaining records are flushed
+// with a context expiring after closeDeadline, which also cancels a periodic flush still in progress.
+// A writer closed before it started only does the final flush
+func BufferedWriter[T any](flush func(ctx context.Context, batch []T) error, capacity int, interval, closeDeadline time.Duration) *bufferedWriterComponent[T] {
+	return &bufferedWriterComponent[T]{
+		flush:         flush,
+		capacity:      capacity,
+		interval:      interval,
+		closeDeadline: closeDeadline,
+		full:          make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Enqueue buffers a record for the next flush. It returns false if the record was dropped,
+// because the buffer is full or the writer is closed
+func (b *bufferedWriterComponent[T]) Enqueue(record T) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed || len(b.buffer) >= b.capacity {
+		b.stats.Dropped++
+		return false
+	}
+
+	b.buffer = append(b.buffer, record)
+	if len(b.buffer) >= b.capacity {
+		select {
+		case b.full <- struct{}{}:
+		default:
+			// Flush already requested
+		}
+	}
+	return true
+}
+
+// Stats returns how many records have been flushed and dropped so far
+func (b *bufferedWriterComponent[T]) Stats() BufferedWriterStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stats
+}
+
+// Setup allows the writer to be started again after it was closed, e.g. on a restart
+func (b *bufferedWriterComponent[T]) Setup() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed, b.started = false, false
+	b.stop, b.done = make(chan struct{}), make(chan struct{})
+	return nil
+}
+
+func (b *bufferedWriterComponent[T]) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		cancel()
+		return nil // Closed before it started
+	}
+	if b.started {
+		b.mu.Unlock()
+		cancel()
+		return fmt.Errorf("buffered writer already started")
+	}
+	b.started = true
+	b.cancel = cancel
+	stop, done := b.stop, b.done
+	b.mu.Unlock()
+	defer close(done)
+	defer cancel()
+
+	t := time.NewTicker(b.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-t.C:
+		case <-b.full:
+		}
+		_ = b.flushBuffered(ctx) // Failures are counted as dropped in the stats
+	}
+}
+
+// Close stops accepting records and flushes whatever is left in the buffer. Closing it again does nothing
+func (b *bufferedWriterComponent[T]) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), b.closeDeadline)
+	defer cancel()
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	started, stop, done, cancelRun := b.started, b.stop, b.done, b.cancel
+	b.started = false
+	b.mu.Unlock()
+
+	if started {
+		close(stop)
+		stopCancel := context.AfterFunc(ctx, cancelRun) // Don't wait past the deadline for a periodic flush
+		<-done
+		stopCancel()
+	}
+
+	if err := b.flushBuffered(ctx); err != nil {
+		stats := b.Stats()
+		return fmt.Errorf("final flush failed (flushed %d, dropped %d records): %w", stats.Flushed, stats.Dropped, err)
+	}
+	return nil
+}
+
+func (b *bufferedWriterComponent[T]) flushBuffered(ctx context.Context) error {
+	b.mu.Lock()
+	batch := b.buffer
+	b.buffer = nil
+	b.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := b.flush(ctx, batch)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err != nil {
+		b.stats.Dropped += len(batch)
+		return err
+	}
+	b.stats.Flushed += len(batch)
+	return nil
+}
@@ -0,0 +1,110 @@
+package unixcycle_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestBufferedWriter(t *testing.T) {
+	t.Run("should flush when full and on close", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			batches [][]int
+			flush   = func(ctx context.Context, batch []int) error {
+				mu.Lock()
+				defer mu.Unlock()
+				batches = append(batches, batch)
+				return nil
+			}
+			sut     = unixcycle.BufferedWriter(flush, 2, time.Hour, time.Second)
+			stopped = make(chan error)
+		)
+		go func() { stopped <- sut.Start() }()
+
+		assert.True(t, sut.Enqueue(1))
+		assert.True(t, sut.Enqueue(2))
+		assert.Eventually(t, func() bool { return sut.Stats().Flushed == 2 }, time.Second, 10*time.Millisecond)
+		assert.True(t, sut.Enqueue(3))
+		err := sut.Close()
+
+		require.NoError(t, err)
+		require.NoError(t, <-stopped)
+		assert.Equal(t, [][]int{{1, 2}, {3}}, batches)
+		assert.False(t, sut.Enqueue(4), "should drop records after close")
+		assert.Equal(t, unixcycle.BufferedWriterStats{Flushed: 3, Dropped: 1}, sut.Stats())
+	})
+
+	t.Run("should report dropped records if the final flush fails", func(t *testing.T) {
+		var (
+			flush = func(ctx context.Context, batch []int) error { return assert.AnError }
+			sut   = unixcycle.BufferedWriter(flush, 10, time.Hour, time.Second)
+		)
+		sut.Enqueue(1)
+		sut.Enqueue(2)
+
+		err := sut.Close()
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "dropped 2 records")
+	})
+
+	t.Run("should cancel a periodic flush still in progress after the close deadline", func(t *testing.T) {
+		var (
+			flushing = make(chan struct{}, 1)
+			flush    = func(ctx context.Context, batch []int) error {
+				flushing <- struct{}{}
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			sut     = unixcycle.BufferedWriter(flush, 1, time.Hour, 10*time.Millisecond)
+			stopped = make(chan error, 1)
+		)
+		go func() { stopped <- sut.Start() }()
+		sut.Enqueue(1)
+		<-flushing
+
+		require.NoError(t, sut.Close())
+		require.NoError(t, <-stopped)
+		assert.Equal(t, unixcycle.BufferedWriterStats{Dropped: 1}, sut.Stats())
+	})
+
+	t.Run("should accept and flush records again after a restart", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			flushed []int
+			flush   = func(ctx context.Context, batch []int) error {
+				mu.Lock()
+				defer mu.Unlock()
+				flushed = append(flushed, batch...)
+				return nil
+			}
+			writer = unixcycle.BufferedWriter(flush, 10, time.Hour, time.Second)
+			sut    = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).Add("writer", writer)
+			handle = sut.RunAsync()
+		)
+		<-sut.Started()
+		require.True(t, writer.Enqueue(1))
+
+		require.NoError(t, sut.Restart("writer"))
+		require.True(t, writer.Enqueue(2), "should accept records after the restart")
+		handle.Signal(0)
+		got := <-handle.Done()
+
+		assert.NoError(t, got.Err)
+		assert.Equal(t, []int{1, 2}, flushed)
+	})
+
+	t.Run("should do nothing when closed twice", func(t *testing.T) {
+		sut := unixcycle.BufferedWriter(func(ctx context.Context, batch []int) error { return nil }, 10, time.Hour, time.Second)
+
+		require.NoError(t, sut.Close())
+
+		assert.NoError(t, sut.Close())
+	})
+}
@@ -0,0 +1,97 @@
+package unixcycle
+
+// Code is a stable machine-readable identifier of a lifecycle event or error.
+// Codes are logged as the "code" attribute, so alerting rules and runbooks don't have to key off log messages
+type Code string
+
+const (
+	CodeComponentInvalid  Code = "UC-COMPONENT-INVALID"
+	CodeDependencyInvalid Code = "UC-DEPENDENCY-INVALID"
+
+	CodeSetupBegin   Code = "UC-SETUP-BEGIN"
+	CodeSetupTimeout Code = "UC-SETUP-TIMEOUT"
+	CodeSetupFailed  Code = "UC-SETUP-FAILED"
+	// CodeRollbackBegin is logged when the components set up before a setup failure are being closed again
+	CodeRollbackBegin Code = "UC-ROLLBACK-BEGIN"
+
+	CodeStartBegin  Code = "UC-START-BEGIN"
+	CodeStartPanic  Code = "UC-START-PANIC"
+	CodeStartFailed Code = "UC-START-FAILED"
+	CodeRestart     Code = "UC-RESTART"
+	// CodeGroupBegin is logged when the components of a startup group are being started
+	CodeGroupBegin   Code = "UC-GROUP-BEGIN"
+	CodeReadyTimeout Code = "UC-READY-TIMEOUT"
+	CodeReadyFailed  Code = "UC-READY-FAILED"
+	// CodeReadinessProbeFailed is logged when the readiness prober fails once all components are started, see WithReadinessProber
+	CodeReadinessProbeFailed Code = "UC-READINESS-PROBE-FAILED"
+	// CodeStarted is logged once all components are started and ready
+	CodeStarted Code = "UC-STARTED"
+
+	CodeSignalReceived Code = "UC-SIGNAL-RECEIVED"
+	CodeContextDone    Code = "UC-CONTEXT-DONE"
+	CodeShutdownDelay  Code = "UC-SHUTDOWN-DELAY"
+
+	CodeReloadBegin  Code = "UC-RELOAD-BEGIN"
+	CodeReloadFailed Code = "UC-RELOAD-FAILED"
+	// CodeConfigInvalid is logged when a ConfigWatcher can't read or parse its file, keeping the previous configuration
+	CodeConfigInvalid Code = "UC-CONFIG-INVALID"
+
+	CodeSignalHookFailed    Code = "UC-SIGNAL-HOOK-FAILED"
+	CodeSignalForward       Code = "UC-SIGNAL-FORWARD"
+	CodeSignalForwardFailed Code = "UC-SIGNAL-FORWARD-FAILED"
+
+	CodeRunCancelTimeout Code = "UC-RUN-CANCEL-TIMEOUT"
+	// CodeTimedOutReturned is logged when an operation of a component returns after it timed out
+	CodeTimedOutReturned Code = "UC-TIMED-OUT-RETURNED"
+	// CodeTimedOutRunning is logged when operations of components are still running after timing out once the manager is done
+	CodeTimedOutRunning Code = "UC-TIMED-OUT-RUNNING"
+
+	CodeDrainBegin   Code = "UC-DRAIN-BEGIN"
+	CodeDrainTimeout Code = "UC-DRAIN-TIMEOUT"
+	CodeDrainFailed  Code = "UC-DRAIN-FAILED"
+
+	CodeCheckpointBegin   Code = "UC-CHECKPOINT-BEGIN"
+	CodeCheckpointTimeout Code = "UC-CHECKPOINT-TIMEOUT"
+	CodeCheckpointFailed  Code = "UC-CHECKPOINT-FAILED"
+
+	CodeCloseBegin   Code = "UC-CLOSE-BEGIN"
+	CodeCloseTimeout Code = "UC-CLOSE-TIMEOUT"
+	CodeCloseFailed  Code = "UC-CLOSE-FAILED"
+	// CodeCloseDeadlock is a close timeout where the start goroutine had already returned,
+	// which usually means Close is blocked on a channel Start no longer serves
+	CodeCloseDeadlock Code = "UC-CLOSE-DEADLOCK"
+
+	CodeStateReadFailed    Code = "UC-STATE-READ-FAILED"
+	CodeStateRestoreBegin  Code = "UC-STATE-RESTORE-BEGIN"
+	CodeStateRestoreFailed Code = "UC-STATE-RESTORE-FAILED"
+	CodeStateSaveBegin     Code = "UC-STATE-SAVE-BEGIN"
+	CodeStateSaveFailed    Code = "UC-STATE-SAVE-FAILED"
+	CodeStateWriteFailed   Code = "UC-STATE-WRITE-FAILED"
+
+	// CodeTiming is logged at debug level with how long an operation of a component or a whole phase took
+	CodeTiming              Code = "UC-TIMING"
+	CodeResourceUsage       Code = "UC-RESOURCE-USAGE"
+	CodeStatusPageFailed    Code = "UC-STATUS-PAGE-FAILED"
+	CodeSystemdNotifyFailed Code = "UC-SYSTEMD-NOTIFY-FAILED"
+	CodeTestProberFailed    Code = "UC-TEST-PROBER-FAILED"
+	// CodeTestTeardownFailed is logged by RunTestMain when the fixtures or components fail to shut down after the tests ran
+	CodeTestTeardownFailed Code = "UC-TEST-TEARDOWN-FAILED"
+
+	CodeUpgradeBegin  Code = "UC-UPGRADE-BEGIN"
+	CodeUpgradeFailed Code = "UC-UPGRADE-FAILED"
+	// CodeUpgradeReady is logged when the new process of an upgrade is ready and this one shuts down
+	CodeUpgradeReady Code = "UC-UPGRADE-READY"
+
+	CodeLeaderAcquired Code = "UC-LEADER-ACQUIRED"
+	CodeLeaderLost     Code = "UC-LEADER-LOST"
+	// CodeLeaderElectionFailed is logged when the election of the leader gate fails, which shuts the manager down
+	CodeLeaderElectionFailed Code = "UC-LEADER-ELECTION-FAILED"
+
+	// CodeManagerMisused is logged when a manager is started twice, or a component is added after it started
+	CodeManagerMisused Code = "UC-MANAGER-MISUSED"
+	// CodeForceExit is logged when the shutdown takes longer than WithForceExitAfter allows, right before the process exits
+	CodeForceExit Code = "UC-FORCE-EXIT"
+
+	// CodeAdminCommand is logged for every command received on the admin socket, see Manager.AdminSocket
+	CodeAdminCommand Code = "UC-ADMIN-COMMAND"
+)
@@ -0,0 +1,145 @@
+package unixcycle
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var _ Component = &commandComponent{}
+
+// commandComponent runs an external binary for as long as it is started
+type commandComponent struct {
+	name        string
+	args        []string
+	gracePeriod time.Duration
+	configure   func(cmd *exec.Cmd)
+
+	mu      sync.Mutex
+	path    string
+	cmd     *exec.Cmd
+	exited  chan struct{}
+	closing bool
+}
+
+// Command creates a component running the binary name with args, e.g. a sidecar:
+//   - Setup looks up the binary, so a missing binary fails the setup
+//   - Start runs the process and waits for it to exit. Exiting with a non-zero status counts as a failure
+//   - Close sends SIGTERM, and SIGKILL once the grace period (default 3 seconds) has passed
+//
+// The output of the process goes to os.Stdout and os.Stderr unless changed with Configure.
+// Combine it with WithSignalForwarding to pass the exit signal on to the process as soon as shutdown begins
+func Command(name string, args ...string) *commandComponent {
+	return &commandComponent{
+		name:        name,
+		args:        args,
+		gracePeriod: 3 * time.Second,
+	}
+}
+
+// WithGracePeriod sets how long Close waits for the process to exit after SIGTERM before killing it.
+// Keep it below the close timeout of the manager
+func (c *commandComponent) WithGracePeriod(gracePeriod time.Duration) *commandComponent {
+	c.gracePeriod = gracePeriod
+	return c
+}
+
+// Configure sets a function customizing the exec.Cmd (environment, working directory, output etc.)
+// every time before the process is started
+func (c *commandComponent) Configure(configure func(cmd *exec.Cmd)) *commandComponent {
+	c.configure = configure
+	return c
+}
+
+func (c *commandComponent) Setup() error {
+	path, err := exec.LookPath(c.name)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.path = path
+	c.closing = false // Set up again after being closed, e.g. on a restart
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *commandComponent) Start() error {
+	c.mu.Lock()
+	cmd := exec.Command(cmp.Or(c.path, c.name), c.args...)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if c.configure != nil {
+		c.configure(cmd)
+	}
+	if c.closing {
+		c.mu.Unlock()
+		return nil
+	}
+	if err := cmd.Start(); err != nil {
+		c.mu.Unlock()
+		return fmt.Errorf("starting %s: %w", c.name, err)
+	}
+	exited := make(chan struct{})
+	c.cmd, c.exited = cmd, exited
+	c.mu.Unlock()
+
+	err := cmd.Wait()
+	close(exited)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closing {
+		return nil // Stopped by Close
+	}
+	if err != nil {
+		return fmt.Errorf("%s exited: %w", c.name, err)
+	}
+	return nil
+}
+
+// Close sends SIGTERM to the process and kills it if it has not exited after the grace period
+func (c *commandComponent) Close() error {
+	c.mu.Lock()
+	c.closing = true
+	cmd, exited := c.cmd, c.exited
+	c.mu.Unlock()
+	if cmd == nil {
+		return nil
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		_ = cmd.Process.Kill() // No SIGTERM on windows
+	}
+	select {
+	case <-exited:
+		return nil
+	case <-time.After(c.gracePeriod):
+	}
+
+	if err := cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("killing %s after %s: %w", c.name, c.gracePeriod, err)
+	}
+	<-exited
+	return nil
+}
+
+// Process returns the running process, nil when it is not running
+func (c *commandComponent) Process() *os.Process {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cmd == nil {
+		return nil
+	}
+	select {
+	case <-c.exited:
+		return nil
+	default:
+		return c.cmd.Process
+	}
+}
@@ -0,0 +1,122 @@
+//go:build linux
+
+package unixcycle_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestCommand(t *testing.T) {
+	t.Run("should fail setup when the binary does not exist", func(t *testing.T) {
+		sut := unixcycle.Command("does-not-exist-unixcycle")
+
+		assert.Error(t, sut.Setup())
+	})
+
+	t.Run("should terminate the process on close", func(t *testing.T) {
+		var (
+			sut    = unixcycle.Command("sleep", "10")
+			result = runUntilStarted(t, sut)
+		)
+
+		assert.NoError(t, sut.Close())
+		assert.NoError(t, <-result)
+		assert.Nil(t, sut.Process())
+	})
+
+	t.Run("should kill the process when it ignores SIGTERM for the grace period", func(t *testing.T) {
+		var (
+			sut    = unixcycle.Command("sh", "-c", `trap "" TERM; exec sleep 10`).WithGracePeriod(50 * time.Millisecond)
+			result = runUntilStarted(t, sut)
+			began  = time.Now()
+		)
+
+		assert.NoError(t, sut.Close())
+		assert.NoError(t, <-result)
+		assert.Less(t, time.Since(began), 5*time.Second)
+	})
+
+	t.Run("should fail when the process exits with an error", func(t *testing.T) {
+		sut := unixcycle.Command("false")
+		require.NoError(t, sut.Setup())
+
+		assert.ErrorContains(t, sut.Start(), "false exited: exit status 1")
+	})
+
+	t.Run("should run as part of a manager", func(t *testing.T) {
+		sut := unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).
+			Add("sidecar", unixcycle.Command("sleep", "10"))
+		handle := sut.RunAsync()
+		require.Eventually(t, func() bool {
+			return unixcycle.ManagerReadyProber(sut)(context.Background()) == nil
+		}, time.Second, time.Millisecond)
+
+		handle.Signal(int(syscall.SIGTERM))
+
+		assert.NoError(t, (<-handle.Done()).Err)
+	})
+}
+
+func TestCommandProber(t *testing.T) {
+	t.Run("should succeed when the command exits with 0", func(t *testing.T) {
+		sut := unixcycle.CommandProber("true")
+
+		assert.NoError(t, sut(context.Background()))
+	})
+
+	t.Run("should fail with the exit code and output of the command", func(t *testing.T) {
+		sut := unixcycle.CommandProber("sh", "-c", "echo accepting connections; echo no response >&2; exit 2")
+
+		err := sut(context.Background())
+
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 2, exitErr.ExitCode())
+		assert.ErrorContains(t, err, `stdout: "accepting connections\n"`)
+		assert.ErrorContains(t, err, `stderr: "no response\n"`)
+	})
+
+	t.Run("should fail when the binary does not exist", func(t *testing.T) {
+		sut := unixcycle.CommandProber("does-not-exist-unixcycle")
+
+		assert.ErrorIs(t, sut(context.Background()), exec.ErrNotFound)
+	})
+
+	t.Run("should kill the command when the context is done", func(t *testing.T) {
+		var (
+			sut         = unixcycle.CommandProber("sleep", "10")
+			ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+		)
+		defer cancel()
+
+		began := time.Now()
+		err := sut(ctx)
+
+		assert.Error(t, err)
+		assert.Less(t, time.Since(began), 5*time.Second)
+	})
+}
+
+// runUntilStarted sets up and starts the command in the background, returning once its process is running
+func runUntilStarted(t *testing.T, c interface {
+	Setup() error
+	Start() error
+	Process() *os.Process
+}) <-chan error {
+	require.NoError(t, c.Setup())
+	result := make(chan error, 1)
+	go func() { result <- c.Start() }()
+	require.Eventually(t, func() bool {
+		return c.Process() != nil
+	}, time.Second, time.Millisecond)
+	return result
+}
@@ -1,5 +1,10 @@
 package unixcycle
 
+import (
+	"context"
+	"log/slog"
+)
+
 type setupable interface {
 	Setup() error
 }
@@ -8,10 +13,52 @@ type startable interface {
 	Start() error
 }
 
+// runnable is preferred over startable. Its context is cancelled on shutdown once the components are drained, before any component is closed
+type runnable interface {
+	Run(ctx context.Context) error
+}
+
+// readyable lets the manager wait for a started component to be ready,
+// before starting the components depending on it and the next startup group
+type readyable interface {
+	Ready(ctx context.Context) error
+}
+
+// drainable is drained on all components when shutdown starts, before any component is checkpointed or closed.
+// It should stop taking in new work and finish the work in flight, while downstream components are still alive
+type drainable interface {
+	Drain(ctx context.Context) error
+}
+
+// loggerAware is handed a child of the manager's logger, tagged with the component name, before setup
+type loggerAware interface {
+	SetLogger(logger *slog.Logger)
+}
+
+type checkpointable interface {
+	Checkpoint(ctx context.Context) error
+}
+
 type closable interface {
 	Close() error
 }
 
+// contextClosable is preferred over closable. Its context expires with the close deadline of the component:
+// its close timeout, or the end of the shutdown budget if that comes first.
+// So it can fall back to a hard stop before the manager gives up on it
+type contextClosable interface {
+	CloseContext(ctx context.Context) error
+}
+
+// StateSaver is an optional interface for components that need state (queue offsets, in-progress batches etc.)
+// to survive a graceful restart. See WithStateFile
+type StateSaver interface {
+	// SaveState is called during shutdown, before the component is closed
+	SaveState() ([]byte, error)
+	// RestoreState is called before setup, with the state saved by the previous run
+	RestoreState(state []byte) error
+}
+
 type Component interface {
 	// Start is the long running part of a "Component"
 	Start() error
@@ -19,7 +66,9 @@ type Component interface {
 
 type namedComponent struct {
 	Component
-	name string
+	name    string
+	options componentOptions
+	status  *componentStatus
 }
 
 var _ Component = &setupComponent{}
@@ -59,3 +108,32 @@ func (c *closerComponent) Close() error {
 func (c *closerComponent) Start() error {
 	return nil
 }
+
+var _ Component = &contextCloserComponent{}
+
+type contextCloserComponent struct {
+	closeFunc func(ctx context.Context) error
+}
+
+func (c *contextCloserComponent) CloseContext(ctx context.Context) error {
+	return c.closeFunc(ctx)
+}
+
+func (c *contextCloserComponent) Start() error {
+	return nil
+}
+
+var _ Component = &runnerComponent{}
+
+type runnerComponent struct {
+	runFunc func(ctx context.Context) error
+}
+
+func (r *runnerComponent) Run(ctx context.Context) error {
+	return r.runFunc(ctx)
+}
+
+// Start is only used when the component is run outside of a manager
+func (r *runnerComponent) Start() error {
+	return r.runFunc(context.Background())
+}
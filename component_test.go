@@ -0,0 +1,53 @@
+package unixcycle_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestClosedBeforeStarted(t *testing.T) {
+	var (
+		worked atomic.Int32 // Counts the jobs, runs, fetches and flushes of all components
+		work   = func(ctx context.Context) error {
+			worked.Add(1)
+			return nil
+		}
+		jobs   = make(chan int, 1)
+		writer = unixcycle.BufferedWriter(func(ctx context.Context, batch []int) error { return work(ctx) }, 10, time.Millisecond, time.Second)
+	)
+	jobs <- 1
+	writer.Enqueue(1) // Flushed by Close, but not again by Start
+
+	for _, tc := range []struct {
+		name string
+		sut  interface {
+			Start() error
+			Close() error
+		}
+	}{
+		{"pool", unixcycle.Pool(1, jobs, func(ctx context.Context, job int) error { return work(ctx) }, time.Second)},
+		{"schedule", unixcycle.Every(time.Millisecond, work)},
+		{"consumer", unixcycle.Consumer(
+			func(ctx context.Context) ([]int, error) { return nil, work(ctx) },
+			func(ctx context.Context, msg int) error { return nil },
+			func(ctx context.Context, msgs []int) error { return nil },
+		)},
+		{"buffered writer", writer},
+	} {
+		t.Run("should return from start without working when a "+tc.name+" was closed before it started", func(t *testing.T) {
+			require.NoError(t, tc.sut.Close())
+			worked.Store(0)
+
+			assert.NoError(t, tc.sut.Start())
+			assert.Zero(t, worked.Load())
+		})
+	}
+
+	assert.Len(t, jobs, 1)
+}
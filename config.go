@@ -0,0 +1,167 @@
+package unixcycle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultConfigPollInterval is how often a ConfigWatcher checks its file for changes by default
+const defaultConfigPollInterval = time.Second
+
+// ConfigReloader is implemented by components that can take a new configuration of type T without a restart, see ConfigWatcher
+type ConfigReloader[T any] interface {
+	Reload(config T) error
+}
+
+var _ Component = &configWatcherComponent[any]{}
+
+// configWatcherComponent keeps a parsed configuration file up to date and hands it to the components of a manager
+type configWatcherComponent[T any] struct {
+	manager      *Manager
+	path         string
+	parse        func(data []byte) (T, error)
+	pollInterval time.Duration
+
+	mu     sync.Mutex // Serializes reloads
+	config T
+	data   []byte
+	stat   os.FileInfo
+}
+
+// ConfigWatcher creates a component keeping the configuration file at path, parsed with parse, up to date:
+//   - Setup reads and parses the file, so an invalid configuration fails the setup
+//   - Run checks the file for changes every second, see WithPollInterval. This includes the file being replaced,
+//     like Kubernetes does with mounted ConfigMaps
+//   - Reload, called by Manager.Reload on SIGHUP, reads the file right away
+//
+// When the file changed and parses, the new configuration is passed to every running component of manager
+// implementing ConfigReloader[T], like Manager.Reload does. A configuration that doesn't parse is logged and
+// ignored, the components keep running with the previous one. See Config for the current configuration
+func ConfigWatcher[T any](manager *Manager, path string, parse func(data []byte) (T, error)) *configWatcherComponent[T] {
+	return &configWatcherComponent[T]{
+		manager:      manager,
+		path:         path,
+		parse:        parse,
+		pollInterval: defaultConfigPollInterval,
+	}
+}
+
+// WithPollInterval sets how often the file is checked for changes, 0 to only read it on Reload
+func (c *configWatcherComponent[T]) WithPollInterval(interval time.Duration) *configWatcherComponent[T] {
+	c.pollInterval = interval
+	return c
+}
+
+// Config returns the configuration that was parsed last
+func (c *configWatcherComponent[T]) Config() T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.config
+}
+
+func (c *configWatcherComponent[T]) Setup() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.read()
+	return err
+}
+
+func (c *configWatcherComponent[T]) Run(ctx context.Context) error {
+	if c.pollInterval <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// Like Manager.Reload, don't reload components that are being restarted or closed. Retry on the next tick
+			// instead of waiting, as a restart of the watcher itself waits for Run to return
+			if !c.modified() || !c.manager.lifecycle.TryLock() {
+				continue
+			}
+			_ = c.reload() // Failures are logged
+			c.manager.lifecycle.Unlock()
+		}
+	}
+}
+
+// Start is only used when the component is run outside of a manager
+func (c *configWatcherComponent[T]) Start() error {
+	return c.Run(context.Background())
+}
+
+// Reload reads the file and passes the configuration on if it changed. It is called by Manager.Reload, e.g. on SIGHUP
+func (c *configWatcherComponent[T]) Reload() error {
+	return c.reload()
+}
+
+func (c *configWatcherComponent[T]) reload() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	changed, err := c.read()
+	if err != nil {
+		c.manager.logError(CodeConfigInvalid, fmt.Sprintf("Failure reading configuration %q, keeping the previous one: %v", c.path, err), slog.String("config_file", c.path))
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
+	config := c.config
+	return c.manager.reloadComponents(func(component Component) func() error {
+		if reloader, ok := component.(ConfigReloader[T]); ok {
+			return func() error { return reloader.Reload(config) }
+		}
+		return nil
+	})
+}
+
+// modified reports whether the file looks different from when it was read last
+func (c *configWatcherComponent[T]) modified() bool {
+	stat, err := os.Stat(c.path)
+	if err != nil {
+		return false // E.g. being replaced, Reload reports it
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stat == nil || !stat.ModTime().Equal(c.stat.ModTime()) || stat.Size() != c.stat.Size()
+}
+
+// read parses the file, keeping the configuration and reporting whether it changed. The caller holds mu
+func (c *configWatcherComponent[T]) read() (changed bool, err error) {
+	stat, err := os.Stat(c.path)
+	if err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return false, err
+	}
+	c.stat = stat // Don't parse an invalid file over and over again
+	if c.data != nil && bytes.Equal(data, c.data) {
+		return false, nil
+	}
+
+	config, err := c.parse(data)
+	if err != nil {
+		return false, fmt.Errorf("parsing configuration %q: %w", c.path, err)
+	}
+	c.config, c.data = config, data
+	return true, nil
+}
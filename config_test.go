@@ -0,0 +1,106 @@
+package unixcycle_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestConfigWatcher(t *testing.T) {
+	var (
+		parse = func(data []byte) (int, error) { return strconv.Atoi(string(data)) }
+		write = func(t *testing.T, path, content string) {
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		}
+	)
+
+	t.Run("should fail the setup when the configuration does not parse", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config")
+		write(t, path, "not a number")
+		sut := unixcycle.NewManager(unixcycle.WithLifetime(func() int { return 0 }))
+		sut.Add("config", unixcycle.ConfigWatcher(sut, path, parse))
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, unixcycle.PhaseSetup, got.FailedPhase)
+	})
+
+	t.Run("should pass changed configurations to the components and ignore invalid ones", func(t *testing.T) {
+		var (
+			path     = filepath.Join(t.TempDir(), "config")
+			reloader = &configReloader{}
+			shutdown = make(chan int, 1)
+			sut      = unixcycle.NewManager(unixcycle.WithLifetime(func() int { return <-shutdown }))
+		)
+		write(t, path, "1")
+		watcher := unixcycle.ConfigWatcher(sut, path, parse).WithPollInterval(time.Millisecond)
+		sut.Add("config", watcher).Add("server", reloader)
+		done := make(chan int)
+		go func() { done <- sut.Run() }()
+		<-sut.Started()
+		assert.Equal(t, 1, watcher.Config())
+
+		write(t, path, "22")
+		require.Eventually(t, func() bool { return slices.Equal(reloader.received(), []int{22}) }, time.Second, time.Millisecond)
+		write(t, path, "not a number")
+		time.Sleep(20 * time.Millisecond)
+		shutdown <- 0
+		<-done
+
+		assert.Equal(t, []int{22}, reloader.received())
+		assert.Equal(t, 22, watcher.Config())
+	})
+
+	t.Run("should read the configuration on Reload, e.g. on SIGHUP", func(t *testing.T) {
+		var (
+			path     = filepath.Join(t.TempDir(), "config")
+			reloader = &configReloader{}
+			shutdown = make(chan int, 1)
+			sut      = unixcycle.NewManager(unixcycle.WithLifetime(func() int { return <-shutdown }))
+		)
+		write(t, path, "1")
+		sut.Add("config", unixcycle.ConfigWatcher(sut, path, parse).WithPollInterval(0)).Add("server", reloader)
+		done := make(chan int)
+		go func() { done <- sut.Run() }()
+		<-sut.Started()
+
+		require.NoError(t, sut.Reload())
+		write(t, path, "2")
+		require.NoError(t, sut.Reload())
+		shutdown <- 0
+		<-done
+
+		assert.Equal(t, []int{2}, reloader.received(), "an unchanged configuration should not be passed on")
+	})
+}
+
+// configReloader records the configurations it is reloaded with
+type configReloader struct {
+	mu      sync.Mutex
+	configs []int
+}
+
+func (c *configReloader) Start() error { return nil }
+
+func (c *configReloader) Reload(config int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.configs = append(c.configs, config)
+	return nil
+}
+
+func (c *configReloader) received() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]int(nil), c.configs...)
+}
@@ -0,0 +1,140 @@
+package unixcycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+var _ Component = &consumerComponent[any]{}
+
+// consumerComponent runs a fetch -> handle -> ack loop as its Start.
+// On close it stops fetching, finishes handling the messages it already fetched, acks them and only then returns
+type consumerComponent[T any] struct {
+	fetch  func(ctx context.Context) ([]T, error)
+	handle func(ctx context.Context, msg T) error
+	ack    func(ctx context.Context, msgs []T) error
+
+	mu           sync.Mutex
+	started      bool
+	closed       bool
+	cancel       context.CancelFunc
+	cancelHandle context.CancelFunc
+	done         chan struct{}
+}
+
+// Consumer creates a component for the pattern every Kafka/NATS/SQS consumer needs:
+//   - fetch returns the next batch of messages and must return once ctx is cancelled
+//   - handle processes a single message. Messages failing to be handled are not acked, so they can be redelivered
+//   - ack acknowledges the messages that were handled successfully
+//
+// Messages are fetched until the component is closed, after which the buffered messages are still handled and acked.
+// Handlers still running when the close timeout expires have their context cancelled.
+// A consumer closed before it started doesn't fetch at all
+func Consumer[T any](
+	fetch func(ctx context.Context) ([]T, error),
+	handle func(ctx context.Context, msg T) error,
+	ack func(ctx context.Context, msgs []T) error,
+) *consumerComponent[T] {
+	return &consumerComponent[T]{
+		fetch:  fetch,
+		handle: handle,
+		ack:    ack,
+	}
+}
+
+// Setup allows the consumer to be started again after it was closed, e.g. on a restart
+func (c *consumerComponent[T]) Setup() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = false
+	return nil
+}
+
+func (c *consumerComponent[T]) Start() error {
+	fetchCtx, cancel := context.WithCancel(context.Background())
+	handleCtx, cancelHandle := context.WithCancel(context.Background())
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		cancel()
+		cancelHandle()
+		return nil // Closed before it started
+	}
+	if c.started {
+		c.mu.Unlock()
+		cancel()
+		cancelHandle()
+		return fmt.Errorf("consumer already started")
+	}
+	c.started = true
+	c.cancel, c.cancelHandle, c.done = cancel, cancelHandle, make(chan struct{})
+	done := c.done
+	c.mu.Unlock()
+	defer close(done)
+	defer cancel()
+	defer cancelHandle()
+
+	for {
+		msgs, err := c.fetch(fetchCtx)
+		if err != nil && fetchCtx.Err() == nil {
+			return fmt.Errorf("fetching messages: %w", err)
+		}
+
+		// Buffered messages are handled and acked even when closing, so processing is not bound to fetchCtx
+		if err := c.handleAndAck(handleCtx, msgs); err != nil {
+			return err
+		}
+
+		if fetchCtx.Err() != nil {
+			return nil // Closed
+		}
+	}
+}
+
+func (c *consumerComponent[T]) handleAndAck(ctx context.Context, msgs []T) error {
+	handled := make([]T, 0, len(msgs))
+	for _, msg := range msgs {
+		if err := c.handle(ctx, msg); err != nil {
+			continue // Not acked, so it will be redelivered
+		}
+		handled = append(handled, msg)
+	}
+	if len(handled) == 0 {
+		return nil
+	}
+
+	if err := c.ack(ctx, handled); err != nil {
+		return fmt.Errorf("acking %d messages: %w", len(handled), err)
+	}
+	return nil
+}
+
+// CloseContext stops fetching and waits for the buffered messages to be handled and acked.
+// Once ctx expires, the context of the handlers still running is cancelled
+func (c *consumerComponent[T]) CloseContext(ctx context.Context) error {
+	c.mu.Lock()
+	started, cancel, cancelHandle, done := c.started, c.cancel, c.cancelHandle, c.done
+	c.started = false
+	c.closed = true // Until set up again, e.g. on a restart
+	c.mu.Unlock()
+	if !started {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	cancelHandle()
+	<-done
+	return fmt.Errorf("buffered messages were not handled in time and were cancelled: %w", ctx.Err())
+}
+
+// Close stops fetching and waits for the buffered messages to be handled and acked, without a deadline
+func (c *consumerComponent[T]) Close() error {
+	return c.CloseContext(context.Background())
+}
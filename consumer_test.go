@@ -0,0 +1,94 @@
+package unixcycle_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestConsumer(t *testing.T) {
+	t.Run("should handle and ack buffered messages before close returns", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			acked   []int
+			fetched = make(chan struct{})
+			fetch   = func() func(ctx context.Context) ([]int, error) {
+				calls := 0
+				return func(ctx context.Context) ([]int, error) {
+					calls++
+					if calls == 1 {
+						close(fetched)
+						<-ctx.Done() // Closed while the batch is buffered
+						return []int{1, 2, 3}, ctx.Err()
+					}
+					t.Error("should not fetch after close")
+					return nil, nil
+				}
+			}()
+			handle = func(ctx context.Context, msg int) error {
+				if msg == 2 {
+					return assert.AnError
+				}
+				return nil
+			}
+			ack = func(ctx context.Context, msgs []int) error {
+				mu.Lock()
+				defer mu.Unlock()
+				acked = append(acked, msgs...)
+				return nil
+			}
+			sut     = unixcycle.Consumer(fetch, handle, ack)
+			stopped = make(chan error)
+		)
+		go func() { stopped <- sut.Start() }()
+		<-fetched
+
+		err := sut.Close()
+
+		require.NoError(t, err)
+		require.NoError(t, <-stopped)
+		assert.Equal(t, []int{1, 3}, acked, "messages failing to be handled should not be acked")
+	})
+
+	t.Run("should cancel the handlers still running once the close timeout expires", func(t *testing.T) {
+		var (
+			handling = make(chan struct{})
+			fetch    = func(ctx context.Context) ([]int, error) { return []int{1}, nil }
+			handle   = func(ctx context.Context, msg int) error {
+				close(handling)
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			ack     = func(ctx context.Context, msgs []int) error { return nil }
+			sut     = unixcycle.Consumer(fetch, handle, ack)
+			stopped = make(chan error)
+		)
+		go func() { stopped <- sut.Start() }()
+		<-handling
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := sut.CloseContext(ctx)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NoError(t, <-stopped)
+	})
+
+	t.Run("should fail start if fetching fails", func(t *testing.T) {
+		var (
+			fetch  = func(ctx context.Context) ([]int, error) { return nil, assert.AnError }
+			handle = func(ctx context.Context, msg int) error { return nil }
+			ack    = func(ctx context.Context, msgs []int) error { return nil }
+			sut    = unixcycle.Consumer(fetch, handle, ack)
+		)
+
+		err := sut.Start()
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -0,0 +1,79 @@
+package unixcycle
+
+import (
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"sync"
+)
+
+var _ Component = &debugServerComponent{}
+
+// lastToClose is implemented by components the manager sets up first and closes last,
+// so they stay available for the whole lifecycle
+type lastToClose interface {
+	closeLast()
+}
+
+// debugServerComponent serves net/http/pprof and expvar for diagnosing a running or stuck process
+type debugServerComponent struct {
+	boundListener
+	addr    string
+	handler http.Handler
+
+	mu     sync.Mutex
+	server *http.Server
+}
+
+// DebugServer creates a component serving net/http/pprof under /debug/pprof/ and expvar under /debug/vars on addr.
+// The manager sets it up first and closes it last, so profiles can still be taken while diagnosing a stuck shutdown.
+// Only bind it to an address that is not publicly reachable
+func DebugServer(addr string) *debugServerComponent {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	return &debugServerComponent{
+		addr:    addr,
+		handler: mux,
+	}
+}
+
+func (d *debugServerComponent) closeLast() {}
+
+// Setup listens on the address, so a port that is already in use fails the setup
+func (d *debugServerComponent) Setup() error {
+	if err := d.listen("tcp", d.addr); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.server = &http.Server{Handler: d.handler} // A closed server can not serve again, e.g. after a restart
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *debugServerComponent) Start() error {
+	d.mu.Lock()
+	server := d.server
+	d.mu.Unlock()
+
+	err := server.Serve(d.bound())
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Close closes the server right away, as in-flight profiles may take longer than any close timeout
+func (d *debugServerComponent) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.server.Close()
+}
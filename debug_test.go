@@ -0,0 +1,44 @@
+package unixcycle_test
+
+import (
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestDebugServer(t *testing.T) {
+	t.Run("should serve pprof and expvar until every other component is closed", func(t *testing.T) {
+		var (
+			debug         = unixcycle.DebugServer("127.0.0.1:0")
+			closeStatuses = make(chan int, 1)
+			getVars       = func() error {
+				resp, err := http.Get("http://" + debug.Addr() + "/debug/vars")
+				if err != nil {
+					return err
+				}
+				closeStatuses <- resp.StatusCode
+				return resp.Body.Close()
+			}
+			sut = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).
+				Add("worker", unixcycle.Closer(getVars)).
+				Add("debug", debug)
+			handle = sut.RunAsync()
+		)
+		require.Eventually(t, func() bool { return debug.Addr() != "" }, time.Second, time.Millisecond)
+
+		resp, err := http.Get("http://" + debug.Addr() + "/debug/pprof/")
+		require.NoError(t, err)
+		resp.Body.Close()
+		handle.Signal(int(syscall.SIGTERM))
+		got := <-handle.Done()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NoError(t, got.Err)
+		assert.Equal(t, http.StatusOK, <-closeStatuses)
+	})
+}
@@ -0,0 +1,104 @@
+package unixcycle
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// orderComponents sorts the components topologically by their dependencies, so every component is set up
+// and started after the components it depends on, and closed before them
+func (m *Manager) orderComponents() error {
+	ordered, err := orderByDependencies(m.components)
+	if err != nil {
+		m.logError(CodeDependencyInvalid, fmt.Sprintf("Invalid dependencies: %v", err))
+		return err
+	}
+
+	m.mu.Lock()
+	m.components = ordered
+	m.mu.Unlock()
+	return nil
+}
+
+// orderByDependencies returns the components sorted topologically by their dependencies.
+// Components without a dependency between them are sorted by their Priority, lowest first, and keep the order
+// they were added in within the same priority. Components implementing lastToClose come first of all,
+// as far as their dependencies allow
+func orderByDependencies(components []namedComponent) ([]namedComponent, error) {
+	names := make(map[string]bool, len(components))
+	for _, s := range components {
+		names[s.name] = true
+	}
+	for _, s := range components {
+		for _, dep := range s.options.dependsOn {
+			if !names[dep] {
+				return nil, fmt.Errorf("component %q depends on unknown component %q", s.name, dep)
+			}
+		}
+	}
+
+	var (
+		ordered   = make([]namedComponent, 0, len(components))
+		placed    = make(map[string]bool, len(components))
+		remaining = slices.Clone(components)
+	)
+	for len(remaining) > 0 {
+		satisfied := func(s namedComponent) bool {
+			return !slices.ContainsFunc(s.options.dependsOn, func(dep string) bool { return !placed[dep] })
+		}
+		next := slices.IndexFunc(remaining, func(s namedComponent) bool {
+			_, last := s.Component.(lastToClose)
+			return last && satisfied(s)
+		})
+		if next < 0 {
+			for i, s := range remaining {
+				if satisfied(s) && (next < 0 || s.options.priority < remaining[next].options.priority) {
+					next = i
+				}
+			}
+		}
+		if next < 0 {
+			cycle := make([]string, 0, len(remaining))
+			for _, s := range remaining {
+				cycle = append(cycle, s.name)
+			}
+			return nil, fmt.Errorf("dependency cycle between components %s", strings.Join(cycle, ", "))
+		}
+		ordered = append(ordered, remaining[next])
+		placed[remaining[next].name] = true
+		remaining = slices.Delete(remaining, next, next+1)
+	}
+
+	return ordered, nil
+}
+
+// closingOrder returns the components in the order they are closed: the reverse of the order they were set up in,
+// except that components with a lower close order are closed first, as far as the components depending on them allow
+func closingOrder(components []namedComponent) []namedComponent {
+	ordered, remaining := make([]namedComponent, 0, len(components)), slices.Clone(components)
+	slices.Reverse(remaining)
+	for len(remaining) > 0 {
+		next := -1
+		for i, s := range remaining {
+			hasDependents := slices.ContainsFunc(remaining, func(other namedComponent) bool {
+				return slices.Contains(other.options.dependsOn, s.name)
+			})
+			if !hasDependents && (next < 0 || closeRank(s) < closeRank(remaining[next])) {
+				next = i
+			}
+		}
+		ordered = append(ordered, remaining[next]) // Components are ordered by their dependencies, so there always is one
+		remaining = slices.Delete(remaining, next, next+1)
+	}
+	return ordered
+}
+
+// closeRank is the close order of the component, components implementing lastToClose rank with CloseLast
+func closeRank(s namedComponent) int {
+	if _, last := s.Component.(lastToClose); last {
+		return math.MaxInt
+	}
+	return s.options.closeOrder
+}
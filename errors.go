@@ -0,0 +1,45 @@
+package unixcycle
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors for the ways a component can fail, use errors.Is on the Err of a Result to branch on them
+var (
+	ErrSetupTimeout = errors.New("setup timed out")
+	ErrSetupFailed  = errors.New("setup failed")
+	ErrStartFailed  = errors.New("start failed")
+	ErrNotReady     = errors.New("not ready")
+	ErrReloadFailed = errors.New("reload failed")
+	ErrCloseTimeout = errors.New("close timed out")
+	ErrCloseFailed  = errors.New("close failed")
+)
+
+// Errors for using a manager the wrong way. A manager runs only once, create a new one to run again
+var (
+	ErrAlreadyRunning = errors.New("manager already running")
+	ErrAlreadyStopped = errors.New("manager already stopped")
+	ErrAddAfterStart  = errors.New("cannot add after start")
+)
+
+// ComponentError is the error of a single component failing. Use errors.As to learn which component failed
+type ComponentError struct {
+	Component string
+	// Kind is one of the sentinel errors, e.g. ErrSetupFailed
+	Kind error
+	// Err is the error the component failed with
+	Err error
+}
+
+func (e *ComponentError) Error() string {
+	return fmt.Sprintf("component %q: %v: %v", e.Component, e.Kind, e.Err)
+}
+
+func (e *ComponentError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+func newComponentError(s namedComponent, kind error, err error) *ComponentError {
+	return &ComponentError{Component: s.name, Kind: kind, Err: err}
+}
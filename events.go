@@ -0,0 +1,88 @@
+package unixcycle
+
+import "time"
+
+// EventKind is the kind of a lifecycle Event
+type EventKind string
+
+const (
+	EventSetupStarted EventKind = "setup started"
+	EventSetUp        EventKind = "set up"
+	EventStarted      EventKind = "started"
+	EventStopped      EventKind = "stopped" // Start returned without an error
+	EventDraining     EventKind = "draining"
+	EventFailed       EventKind = "failed"
+	EventClosing      EventKind = "closing"
+	EventClosed       EventKind = "closed"
+	EventStandby      EventKind = "standby"
+
+	// EventAllStarted is emitted once all components are started and ready, see Manager.Started
+	EventAllStarted EventKind = "all started"
+	// EventShutdownBegan is emitted once the exit signal is received, before any component is stopped
+	EventShutdownBegan EventKind = "shutdown began"
+	// EventShutdownCompleted is emitted once all components are closed, with the error of the Result if any
+	EventShutdownCompleted EventKind = "shutdown completed"
+	// EventResourceUsage is emitted with every report of WithResourceUsageReporting
+	EventResourceUsage EventKind = "resource usage"
+)
+
+// Event is a change in the lifecycle of a component or the manager
+type Event struct {
+	Kind EventKind
+	// Component is the name of the component, empty for events of the manager itself
+	Component string
+	Time      time.Time
+	// Code is the code of the log line describing the event, empty if there is none, see Code
+	Code Code
+	// Err is the error the component failed with, or the error of the Result on EventShutdownCompleted
+	Err error
+	// Usage is the reported snapshot on EventResourceUsage
+	Usage *ResourceUsage
+}
+
+// eventKinds maps the states of components to the event emitted when a component enters them
+var eventKinds = map[ComponentState]EventKind{
+	StateSettingUp: EventSetupStarted,
+	StateSetUp:     EventSetUp,
+	StateRunning:   EventStarted,
+	StateStopped:   EventStopped,
+	StateDraining:  EventDraining,
+	StateFailed:    EventFailed,
+	StateClosing:   EventClosing,
+	StateClosed:    EventClosed,
+	StateStandby:   EventStandby,
+}
+
+// WithEventHandler calls handler with every lifecycle event, e.g. to feed dashboards, metrics or custom logging.
+// Handlers are called synchronously from the goroutine changing the state, so they should return quickly,
+// and in the order they are registered. Can be registered multiple times
+func WithEventHandler(handler func(Event)) managerOption {
+	return func(o *managerOptions) {
+		o.eventHandlers = append(o.eventHandlers, handler)
+	}
+}
+
+// emit passes an event to the event handlers
+func (m *Manager) emit(kind EventKind, component string, code Code, err error) {
+	if len(m.eventHandlers) == 0 {
+		return
+	}
+
+	m.emitEvent(Event{Kind: kind, Component: component, Time: time.Now(), Code: code, Err: err})
+}
+
+// emitEvent passes a fully built event to the event handlers, e.g. one carrying a ResourceUsage
+func (m *Manager) emitEvent(event Event) {
+	for _, handler := range m.eventHandlers {
+		handler(event)
+	}
+}
+
+// shutdownBegun is called once the exit signal is received, before any component is stopped
+func (m *Manager) shutdownBegun() {
+	m.emit(EventShutdownBegan, "", "", nil)
+	for _, hook := range m.onShutdownStart {
+		hook()
+	}
+	m.systemdStopping()
+}
@@ -0,0 +1,76 @@
+package unixcycle_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestEvents(t *testing.T) {
+	t.Run("should emit the lifecycle events of the components and the manager in order", func(t *testing.T) {
+		var (
+			mu     sync.Mutex
+			events []unixcycle.Event
+			sut    = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithEventHandler(func(e unixcycle.Event) {
+					mu.Lock()
+					defer mu.Unlock()
+					events = append(events, e)
+				}),
+			).
+				Add("db", &testComponent{
+					setupFunc: func() error { return nil },
+					startFunc: func() error { select {} },
+					closeFunc: func() error { return nil },
+				})
+			handle = sut.RunAsync()
+		)
+		<-sut.Started()
+		handle.Signal(0)
+		<-handle.Done()
+
+		mu.Lock()
+		defer mu.Unlock()
+		var got []string
+		for _, e := range events {
+			assert.False(t, e.Time.IsZero())
+			got = append(got, e.Component+" "+string(e.Kind))
+		}
+		assert.Equal(t, []string{
+			"db setup started",
+			"db set up",
+			"db started",
+			" all started",
+			" shutdown began",
+			"db closing",
+			"db closed",
+			" shutdown completed",
+		}, got)
+	})
+
+	t.Run("should carry the error of a failing component", func(t *testing.T) {
+		var (
+			failed []unixcycle.Event
+			sut    = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithEventHandler(func(e unixcycle.Event) {
+					if e.Kind == unixcycle.EventFailed {
+						failed = append(failed, e)
+					}
+				}),
+			).
+				Add("worker", unixcycle.Starter(func() error { return assert.AnError }))
+		)
+
+		sut.Run()
+
+		require.Len(t, failed, 1)
+		assert.Equal(t, "worker", failed[0].Component)
+		assert.ErrorIs(t, failed[0].Err, assert.AnError)
+		assert.Equal(t, unixcycle.CodeStartFailed, failed[0].Code)
+	})
+}
@@ -0,0 +1,48 @@
+package unixcycle
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ExitPolicy decides what happens when the Start of a component returns nil while the manager is still running
+type ExitPolicy int
+
+const (
+	// ExitCompletes treats the component as done, e.g. a oneshot task. The other components keep running
+	ExitCompletes ExitPolicy = iota
+	// ExitFails treats the component as failed with ErrUnexpectedExit, applying the supervision strategy
+	ExitFails
+	// ExitRestarts starts the component again, after the first delay of the supervision backoff
+	// (one second without supervision). Restarts after an exit don't count towards the maximum restarts
+	ExitRestarts
+)
+
+// ErrUnexpectedExit is the error of a component with ExitFails whose Start returned nil
+var ErrUnexpectedExit = errors.New("start returned without an error")
+
+// OnExit sets what happens when Start of the component returns nil while the manager is still running,
+// so a long running loop exiting silently does not leave the process running half-dead.
+// Default is ExitCompletes
+func OnExit(policy ExitPolicy) componentOption {
+	return func(o *componentOptions) {
+		o.onExit = policy
+	}
+}
+
+// componentExited applies the exit policy to a component whose Start returned nil.
+// Returns the error to fail the component with, if any
+func (m *Manager) componentExited(s namedComponent, generation int) error {
+	if !m.isCurrent(generation) {
+		return nil // Stopped for a restart or shutdown
+	}
+
+	switch s.options.onExit {
+	case ExitFails:
+		return ErrUnexpectedExit
+	case ExitRestarts:
+		go m.relaunch(s, m.restartDelay(1), fmt.Sprintf("Restarting component %q after it exited", s.name), slog.String("component_name", s.name))
+	}
+	return nil
+}
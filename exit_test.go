@@ -0,0 +1,60 @@
+package unixcycle_test
+
+import (
+	"context"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestOnExit(t *testing.T) {
+	t.Run("should keep running when a component completes by default", func(t *testing.T) {
+		var (
+			sut = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).
+				Add("oneshot", unixcycle.Starter(func() error { return nil }))
+			handle = sut.RunAsync()
+		)
+
+		assert.Eventually(t, func() bool {
+			return sut.Health(context.Background()).Components[0].State == unixcycle.StateStopped
+		}, time.Second, time.Millisecond)
+		handle.Signal(int(syscall.SIGTERM))
+		got := <-handle.Done()
+
+		assert.Equal(t, int(syscall.SIGTERM), got.Signal)
+		assert.NoError(t, got.Err)
+	})
+
+	t.Run("should abort when a component with ExitFails returns nil", func(t *testing.T) {
+		sut := unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).
+			Add("server loop", unixcycle.Starter(func() error { return nil }), unixcycle.OnExit(unixcycle.ExitFails))
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, int(syscall.SIGABRT), got.Signal)
+		assert.ErrorIs(t, got.Err, unixcycle.ErrStartFailed)
+		assert.ErrorIs(t, got.Err, unixcycle.ErrUnexpectedExit)
+	})
+
+	t.Run("should restart a component with ExitRestarts after the supervision backoff", func(t *testing.T) {
+		var (
+			starts atomic.Int32
+			sut    = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithSupervision(unixcycle.NoSupervision, unixcycle.ConstantBackoff(time.Millisecond), 0),
+			).
+				Add("poller", unixcycle.Starter(func() error { starts.Add(1); return nil }), unixcycle.OnExit(unixcycle.ExitRestarts))
+			handle = sut.RunAsync()
+		)
+
+		assert.Eventually(t, func() bool { return starts.Load() >= 3 }, time.Second, time.Millisecond)
+		handle.Signal(int(syscall.SIGTERM))
+		got := <-handle.Done()
+
+		assert.NoError(t, got.Err, "restarts after an exit should not count towards the maximum restarts")
+	})
+}
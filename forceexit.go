@@ -0,0 +1,37 @@
+package unixcycle
+
+import (
+	"fmt"
+	"os"
+	"runtime/pprof"
+	"syscall"
+	"time"
+)
+
+// ForceExitCode is the exit code of a process forced to exit by WithForceExitAfter, as if it was killed with SIGKILL
+const ForceExitCode = int(syscall.SIGKILL)
+
+// WithForceExitAfter exits the process with ForceExitCode when the shutdown has not finished d after the exit signal
+// was received, e.g. because a Close ignores its timeout and holds the lifecycle. The stacks of all goroutines
+// are written to stderr first, to find what wedged the shutdown.
+// This is the last resort when the operator's only signal was already consumed by the shutdown.
+// Default is waiting for the shutdown forever
+func WithForceExitAfter(d time.Duration) managerOption {
+	return func(o *managerOptions) {
+		o.forceExitAfter = d
+	}
+}
+
+// armForceExit forces the process to exit unless stop is called in time, see WithForceExitAfter
+func (m *Manager) armForceExit() (stop func()) {
+	if m.forceExitAfter <= 0 {
+		return func() {}
+	}
+
+	t := time.AfterFunc(m.forceExitAfter, func() {
+		m.logError(CodeForceExit, fmt.Sprintf("Shutdown did not finish within %s, forcing exit with code %d", m.forceExitAfter, ForceExitCode))
+		_ = pprof.Lookup("goroutine").WriteTo(os.Stderr, 2)
+		os.Exit(ForceExitCode)
+	})
+	return func() { t.Stop() }
+}
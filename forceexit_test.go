@@ -0,0 +1,65 @@
+package unixcycle_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+// forceExitChildEnv makes TestForceExitAfterChild act as the wedged process of TestForceExitAfter
+const forceExitChildEnv = "UNIXCYCLE_TEST_FORCE_EXIT_CHILD"
+
+func TestForceExitAfter(t *testing.T) {
+	t.Run("should exit with the force exit code and dump the goroutines when the shutdown hangs", func(t *testing.T) {
+		var (
+			stderr bytes.Buffer
+			cmd    = exec.Command(os.Args[0], "-test.run=^TestForceExitAfterChild$")
+		)
+		cmd.Env = append(os.Environ(), forceExitChildEnv+"=1")
+		cmd.Stderr = &stderr
+
+		err := cmd.Run()
+
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(err, &exitErr), "the process should have failed, got %v", err)
+		assert.Equal(t, unixcycle.ForceExitCode, exitErr.ExitCode())
+		assert.Contains(t, stderr.String(), "goroutine ")
+	})
+
+	t.Run("should not exit when the shutdown finishes in time", func(t *testing.T) {
+		sut := unixcycle.NewManager(
+			unixcycle.WithLifetime(func() int { return 0 }),
+			unixcycle.WithForceExitAfter(50*time.Millisecond),
+		)
+		sut.Add("closer", unixcycle.Closer(func() error { return nil }))
+
+		got := sut.Run()
+		time.Sleep(100 * time.Millisecond) // The process would be gone by now
+
+		assert.Equal(t, 0, got)
+	})
+}
+
+// TestForceExitAfterChild is the process started by TestForceExitAfter, wedged in Close
+func TestForceExitAfterChild(t *testing.T) {
+	if os.Getenv(forceExitChildEnv) == "" {
+		t.Skip("only run as the process started by TestForceExitAfter")
+	}
+
+	sut := unixcycle.NewManager(
+		unixcycle.WithLifetime(func() int { return 0 }),
+		unixcycle.WithCloseTimeout(time.Hour),
+		unixcycle.WithForceExitAfter(100*time.Millisecond),
+	)
+	sut.Add("wedged", unixcycle.Closer(func() error { select {} }))
+	sut.Run()
+
+	os.Exit(0) // Only reached when the process was not forced to exit
+}
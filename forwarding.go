@@ -0,0 +1,52 @@
+package unixcycle
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"syscall"
+)
+
+// processOwner is an optional interface for components running a child process, see WithSignalForwarding.
+// Process returns nil while no process is running
+type processOwner interface {
+	Process() *os.Process
+}
+
+// WithSignalForwarding forwards the exit signal to the child processes of components implementing Process() *os.Process
+// when shutdown begins, before any component is drained or closed, giving external binaries a chance at their own
+// graceful shutdown. SIGINT is forwarded as is, also when InterruptSignal received it, anything else as SIGTERM
+// Default is no forwarding
+func WithSignalForwarding() managerOption {
+	return func(o *managerOptions) {
+		o.forwardSignals = true
+	}
+}
+
+// forwardSignal forwards the exit signal to the child processes of the components, if enabled
+func (m *Manager) forwardSignal(signal int) {
+	if !m.forwardSignals {
+		return
+	}
+
+	forwarded := syscall.SIGTERM
+	if signal == int(syscall.SIGINT) || signal == 0 && interrupted.Swap(0) == int32(syscall.SIGINT) {
+		forwarded = syscall.SIGINT // InterruptSignal returns 0 for Ctrl-C, but the child should still see SIGINT
+	}
+
+	for _, s := range m.components {
+		owner, ok := s.Component.(processOwner)
+		if !ok {
+			continue
+		}
+		process := owner.Process()
+		if process == nil {
+			continue
+		}
+
+		m.logInfo(CodeSignalForward, fmt.Sprintf("Forwarding %v to process %d of component %q", forwarded, process.Pid, s.name), slog.String("component_name", s.name), slog.Int("pid", process.Pid))
+		if err := process.Signal(forwarded); err != nil {
+			m.logError(CodeSignalForwardFailed, fmt.Sprintf("Failed to forward %v to process %d of component %q: %v", forwarded, process.Pid, s.name, err), slog.String("component_name", s.name), slog.Int("pid", process.Pid))
+		}
+	}
+}
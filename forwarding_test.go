@@ -0,0 +1,104 @@
+//go:build linux
+
+package unixcycle_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestWithSignalForwarding(t *testing.T) {
+	t.Run("should forward the exit signal to child processes before closing", func(t *testing.T) {
+		var (
+			child = &processComponent{cmd: exec.Command("sleep", "10"), exited: make(chan error, 1)}
+			sut   = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithSignalForwarding(),
+			).
+				Add("child", child)
+			handle = sut.RunAsync()
+		)
+		require.Eventually(t, func() bool {
+			return unixcycle.ManagerReadyProber(sut)(context.Background()) == nil && child.Process() != nil
+		}, time.Second, time.Millisecond)
+
+		handle.Signal(int(syscall.SIGTERM))
+		got := <-handle.Done()
+
+		assert.NoError(t, got.Err)
+		assert.EqualError(t, <-child.exited, "signal: terminated")
+	})
+
+	t.Run("should forward SIGINT received by the default lifetime as SIGINT", func(t *testing.T) {
+		keepAlive := make(chan os.Signal, 1) // Don't let SIGINT kill the test before the lifetime listens for it
+		signal.Notify(keepAlive, syscall.SIGINT)
+		defer signal.Stop(keepAlive)
+
+		var (
+			child = &processComponent{cmd: exec.Command("sleep", "10"), exited: make(chan error, 1)}
+			sut   = unixcycle.NewManager(
+				unixcycle.WithSignalForwarding(),
+			).
+				Add("child", child)
+			handle = sut.RunAsync()
+		)
+		require.Eventually(t, func() bool {
+			return unixcycle.ManagerReadyProber(sut)(context.Background()) == nil && child.Process() != nil
+		}, time.Second, time.Millisecond)
+
+		require.Eventually(t, func() bool {
+			require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGINT))
+			select {
+			case got := <-handle.Done():
+				assert.NoError(t, got.Err)
+				return true
+			case <-time.After(10 * time.Millisecond):
+				return false // The lifetime might not be listening yet
+			}
+		}, time.Second, time.Millisecond)
+		assert.EqualError(t, <-child.exited, "signal: interrupt")
+	})
+}
+
+// processComponent runs a child process, but leaves stopping it to the forwarded signal
+type processComponent struct {
+	cmd    *exec.Cmd
+	exited chan error
+
+	mu      sync.Mutex
+	started bool
+}
+
+func (c *processComponent) Start() error {
+	c.mu.Lock()
+	err := c.cmd.Start()
+	c.started = err == nil
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	err = c.cmd.Wait()
+	c.exited <- err
+	return err
+}
+
+func (c *processComponent) Process() *os.Process {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.started {
+		return nil
+	}
+	return c.cmd.Process
+}
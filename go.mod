@@ -5,6 +5,7 @@ go 1.23.0
 require (
 	github.com/stretchr/testify v1.10.0
 	golang.org/x/sync v0.14.0
+	golang.org/x/sys v0.33.0
 )
 
 require (
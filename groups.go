@@ -0,0 +1,160 @@
+package unixcycle
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// validateGroups checks the startup groups before starting, see checkGroups
+func (m *Manager) validateGroups() error {
+	err := m.checkGroups()
+	if err != nil {
+		m.logError(CodeDependencyInvalid, fmt.Sprintf("Invalid startup groups: %v", err))
+	}
+	return err
+}
+
+// checkGroups checks that every component is in a declared startup group,
+// and that no component depends on a component that is started in a later group
+func (m *Manager) checkGroups() error {
+	groupIndex := make(map[string]int, len(m.components))
+	for _, s := range m.components {
+		groupIndex[s.name] = m.groupIndex(s)
+		if groupIndex[s.name] < 0 {
+			return fmt.Errorf("component %q is in unknown startup group %q", s.name, s.options.group)
+		}
+	}
+	for _, s := range m.components {
+		for _, dep := range s.options.dependsOn {
+			if groupIndex[dep] > groupIndex[s.name] {
+				return fmt.Errorf("component %q depends on component %q which is started in a later group", s.name, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// groupIndex returns the position of the startup group of the component, or -1 if the group was never declared.
+// Components without a group are started after all declared groups
+func (m *Manager) groupIndex(s namedComponent) int {
+	if s.options.group == "" {
+		return len(m.startupGroups)
+	}
+	return slices.Index(m.startupGroups, s.options.group)
+}
+
+// startComponents launches the components group by group. Within a group components are launched in dependency order,
+// a component only once its dependencies are ready, and the next priority only once the components with lower priorities
+// are ready. The next group is only launched once every component of the group is ready. See awaitReady.
+// Returns whether all groups were started
+func (m *Manager) startComponents() bool {
+	var (
+		runCtx, _ = m.currentRun()
+		byName    = make(map[string]namedComponent, len(m.components))
+		ready     = make(map[string]bool, len(m.components))
+	)
+	for _, s := range m.components {
+		byName[s.name] = s
+	}
+	awaitOnce := func(s namedComponent) bool {
+		if ready[s.name] {
+			return true
+		}
+		ready[s.name] = m.awaitReady(runCtx, s)
+		return ready[s.name]
+	}
+
+	for i := range len(m.startupGroups) + 1 {
+		group := slices.DeleteFunc(slices.Clone(m.components), func(s namedComponent) bool {
+			return m.groupIndex(s) != i || m.state(s) == StateStandby // Started once leadership is acquired
+		})
+		if len(group) == 0 || runCtx.Err() != nil {
+			continue
+		}
+
+		if i < len(m.startupGroups) {
+			m.logInfo(CodeGroupBegin, fmt.Sprintf("Starting group %q", m.startupGroups[i]), slog.String("group", m.startupGroups[i]))
+		}
+		for j, s := range group {
+			if j > 0 && s.options.priority != group[j-1].options.priority {
+				// The next priority is only started once all components with lower priorities are ready
+				for _, launched := range group[:j] {
+					if !awaitOnce(launched) {
+						return false
+					}
+				}
+			}
+			for _, dep := range s.options.dependsOn {
+				if !awaitOnce(byName[dep]) {
+					return false
+				}
+			}
+			m.launch(s)
+		}
+		for _, s := range group {
+			if !awaitOnce(s) {
+				return false
+			}
+		}
+	}
+	return runCtx.Err() == nil
+}
+
+// awaitReady waits for a component implementing readyable or with a readiness prober to become ready, within its setup timeout.
+// Other components are ready once launched. Returns false if the component did not become ready or the components are being stopped
+func (m *Manager) awaitReady(runCtx context.Context, s namedComponent) bool {
+	ready := readyFunc(s)
+	if ready == nil {
+		return runCtx.Err() == nil
+	}
+
+	timeout := cmp.Or(s.options.setupTimeout, m.setupTimeout)
+	ctx, cancel := context.WithTimeout(runCtx, timeout)
+	defer cancel()
+
+	err := m.traced("ready", s, func() error {
+		return funcOrTimeout(func() error { return ready(ctx) }, timeout)
+	})
+	if runCtx.Err() != nil {
+		return false // Stopped for a restart or shutdown while waiting
+	}
+	if err == nil {
+		return true
+	}
+
+	m.trackTimedOut(s, "Ready", err)
+	code := CodeReadyFailed
+	if errors.Is(err, errTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		code = CodeReadyTimeout
+		m.logError(code, fmt.Sprintf("Component %q did not become ready within %s", s.name, timeout), slog.String("component_name", s.name))
+		err = errTimeout
+	} else {
+		m.logError(code, fmt.Sprintf("Component %q failed to become ready: %v", s.name, err), slog.String("component_name", s.name))
+	}
+	m.setState(s, StateFailed, code, err)
+	m.abort(newComponentError(s, ErrNotReady, err))
+	return false
+}
+
+// readyFunc returns the function telling whether a started component is ready: Ready if it implements readyable,
+// followed by its readiness prober, see WithReadiness. Nil if it has neither
+func readyFunc(s namedComponent) func(ctx context.Context) error {
+	readyable, ok := s.Component.(readyable)
+	switch {
+	case ok && s.options.readiness != nil:
+		return func(ctx context.Context) error {
+			if err := readyable.Ready(ctx); err != nil {
+				return err
+			}
+			return s.options.readiness(ctx)
+		}
+	case ok:
+		return readyable.Ready
+	default:
+		return s.options.readiness
+	}
+}
@@ -0,0 +1,66 @@
+package unixcycle
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// grpcServer is the part of *grpc.Server the gRPC component needs, so this package doesn't depend on grpc
+type grpcServer interface {
+	Serve(listener net.Listener) error
+	GracefulStop()
+	Stop()
+}
+
+var _ Component = &grpcServerComponent{}
+
+// grpcServerComponent runs a gRPC server on a listener bound during setup
+type grpcServerComponent struct {
+	boundListener
+	server grpcServer
+	addr   string
+}
+
+// GRPCServer creates a component running a *grpc.Server on addr:
+//   - Setup listens on addr, so a port that is already in use fails the setup
+//   - Start serves
+//   - Close stops gracefully, waiting for pending RPCs, and falls back to a hard Stop
+//     once 90% of the close timeout has passed, so the server is stopped before the manager gives up on it
+func GRPCServer(server grpcServer, addr string) *grpcServerComponent {
+	return &grpcServerComponent{server: server, addr: addr}
+}
+
+func (g *grpcServerComponent) Setup() error {
+	return g.listen("tcp", g.addr)
+}
+
+func (g *grpcServerComponent) Start() error {
+	return g.server.Serve(g.bound()) // Returns nil once stopped
+}
+
+// CloseContext stops the server gracefully, and hard once most of the time until ctx expires has passed
+func (g *grpcServerComponent) CloseContext(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		g.server.GracefulStop()
+		close(stopped)
+	}()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining := time.Until(deadline)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, remaining-remaining/10)
+		defer cancel()
+	}
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+	}
+
+	g.server.Stop()
+	<-stopped
+	return nil
+}
@@ -0,0 +1,59 @@
+package unixcycle_test
+
+import (
+	"net"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestGRPCServer(t *testing.T) {
+	t.Run("should stop hard when the graceful stop does not finish within the close timeout", func(t *testing.T) {
+		var (
+			server = newFakeGRPCServer()
+			sut    = unixcycle.GRPCServer(server, "127.0.0.1:0")
+			m      = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithCloseTimeout(100*time.Millisecond),
+			).Add("grpc", sut)
+		)
+		handle := m.RunAsync()
+		require.Eventually(t, func() bool { return sut.Addr() != "" }, time.Second, time.Millisecond)
+
+		handle.Signal(int(syscall.SIGTERM))
+		got := <-handle.Done()
+
+		assert.NoError(t, got.Err)
+		assert.True(t, server.stoppedHard)
+	})
+}
+
+// fakeGRPCServer never finishes a graceful stop, like a server with a hanging stream
+type fakeGRPCServer struct {
+	stop        chan struct{}
+	once        sync.Once
+	stoppedHard bool
+}
+
+func newFakeGRPCServer() *fakeGRPCServer {
+	return &fakeGRPCServer{stop: make(chan struct{})}
+}
+
+func (s *fakeGRPCServer) Serve(listener net.Listener) error {
+	<-s.stop
+	return listener.Close()
+}
+
+func (s *fakeGRPCServer) GracefulStop() {
+	<-s.stop
+}
+
+func (s *fakeGRPCServer) Stop() {
+	s.stoppedHard = true
+	s.once.Do(func() { close(s.stop) })
+}
@@ -0,0 +1,70 @@
+package unixcycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// healther is an optional interface for components that can check their own health while running, see Manager.Health
+type healther interface {
+	Health(ctx context.Context) error
+}
+
+// ComponentHealth is the health of a single component
+type ComponentHealth struct {
+	Name  string
+	State ComponentState
+	// Err is why the component is unhealthy, nil when it is healthy
+	Err error
+}
+
+// HealthReport is the health of all components of a manager
+type HealthReport struct {
+	// Healthy is true when every component is healthy
+	Healthy    bool
+	Components []ComponentHealth
+}
+
+// Health checks the health of every component, in the order they are set up.
+// A component is healthy while it is running (or its Start returned without an error) and, if it implements
+// Health(ctx) error, that returns nil. Leader-only components waiting for leadership in StateStandby are healthy
+// and not checked. The checks run concurrently and should respect ctx
+func (m *Manager) Health(ctx context.Context) HealthReport {
+	m.mu.Lock()
+	report := HealthReport{Healthy: true, Components: make([]ComponentHealth, len(m.components))}
+	components := make([]namedComponent, len(m.components))
+	for i, s := range m.components {
+		components[i] = s
+		report.Components[i] = ComponentHealth{Name: s.name, State: s.status.state}
+		switch s.status.state {
+		case StateRunning, StateStopped, StateStandby:
+		case StateFailed:
+			report.Components[i].Err = fmt.Errorf("component failed: %w", s.status.lastErr)
+		default:
+			report.Components[i].Err = fmt.Errorf("component is %s", s.status.state)
+		}
+	}
+	m.mu.Unlock() // Don't hold the lock while checking, a component may call back into the manager
+
+	var wg sync.WaitGroup
+	for i, s := range components {
+		healther, ok := s.Component.(healther)
+		if !ok || report.Components[i].Err != nil || report.Components[i].State == StateStandby {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Components[i].Err, _ = callRecovering(func() error { return healther.Health(ctx) })
+		}()
+	}
+	wg.Wait()
+
+	for _, health := range report.Components {
+		if health.Err != nil {
+			report.Healthy = false
+		}
+	}
+	return report
+}
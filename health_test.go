@@ -0,0 +1,89 @@
+package unixcycle_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run("should report components that are not running as unhealthy", func(t *testing.T) {
+		sut := unixcycle.NewManager().Add("worker", unixcycle.Starter(func() error { return nil }))
+
+		got := sut.Health(context.Background())
+
+		assert.False(t, got.Healthy)
+		assert.EqualError(t, got.Components[0].Err, "component is registered")
+	})
+
+	t.Run("should aggregate the health checks of running components", func(t *testing.T) {
+		var (
+			sut = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).
+				Add("worker", unixcycle.Starter(func() error { return nil })).
+				Add("db", &healthComponent{err: assert.AnError, stopped: make(chan struct{})})
+			handle = sut.RunAsync()
+		)
+		defer func() {
+			handle.Signal(int(syscall.SIGTERM))
+			<-handle.Done()
+		}()
+		assert.Eventually(t, func() bool {
+			return unixcycle.ManagerReadyProber(sut)(context.Background()) == nil &&
+				sut.Health(context.Background()).Components[0].State == unixcycle.StateStopped
+		}, time.Second, time.Millisecond, "worker should have returned from start")
+
+		got := sut.Health(context.Background())
+
+		assert.False(t, got.Healthy)
+		assert.Equal(t, []unixcycle.ComponentHealth{
+			{Name: "worker", State: unixcycle.StateStopped},
+			{Name: "db", State: unixcycle.StateRunning, Err: assert.AnError},
+		}, got.Components)
+	})
+
+	t.Run("should report leader-only components of a follower as healthy without checking them", func(t *testing.T) {
+		var (
+			sut = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithLeaderGate(newFakeLeaderGate()),
+			).
+				Add("scheduler", &healthComponent{err: assert.AnError, stopped: make(chan struct{})}, unixcycle.LeaderOnly())
+			handle = sut.RunAsync()
+		)
+		defer func() {
+			handle.Signal(int(syscall.SIGTERM))
+			<-handle.Done()
+		}()
+		<-sut.Started()
+
+		got := sut.Health(context.Background())
+
+		assert.True(t, got.Healthy)
+		assert.Equal(t, []unixcycle.ComponentHealth{
+			{Name: "scheduler", State: unixcycle.StateStandby},
+		}, got.Components)
+	})
+}
+
+type healthComponent struct {
+	err     error
+	stopped chan struct{}
+}
+
+func (c *healthComponent) Start() error {
+	<-c.stopped
+	return nil
+}
+
+func (c *healthComponent) Close() error {
+	close(c.stopped)
+	return nil
+}
+
+func (c *healthComponent) Health(ctx context.Context) error {
+	return c.err
+}
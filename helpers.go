@@ -1,6 +1,7 @@
 package unixcycle
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -22,10 +23,22 @@ func Starter(startFunc func() error) *starterComponent {
 	return &starterComponent{startFunc: startFunc}
 }
 
+// Runner creates a component from a long running function taking a context.
+// The manager cancels the context on shutdown once the components are drained, so no Close with a hand-rolled stop channel is needed
+func Runner(runFunc func(ctx context.Context) error) *runnerComponent {
+	return &runnerComponent{runFunc: runFunc}
+}
+
 func Closer(closeFunc func() error) *closerComponent {
 	return &closerComponent{closeFunc: closeFunc}
 }
 
+// CloserContext is like Closer, but hands closeFunc a context expiring with the close deadline,
+// so it can cut its work short before the manager gives up on it
+func CloserContext(closeFunc func(ctx context.Context) error) *contextCloserComponent {
+	return &contextCloserComponent{closeFunc: closeFunc}
+}
+
 // Type constraint to allow either makeFunc[T] or makeErrorFunc[T]
 type makerConstraint[T any] interface {
 	*T | ~func() *T | func() (*T, error)
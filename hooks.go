@@ -0,0 +1,35 @@
+package unixcycle
+
+import "time"
+
+// Hooks are callbacks for well-known points of the lifecycle, for when a full event stream is more than needed,
+// e.g. registering with service discovery once started and deregistering when shutdown starts. Nil hooks are skipped
+type Hooks struct {
+	// OnSetupComplete is called once all components are set up, before any of them is started
+	OnSetupComplete func()
+	// OnAllStarted is called once all components are started and ready, see WithOnStarted
+	OnAllStarted func(startup time.Duration)
+	// OnShutdownStart is called once the exit signal is received, before any component is stopped
+	OnShutdownStart func()
+	// OnShutdownComplete is called once all components are closed, before Run returns, see WithAfterShutdown
+	OnShutdownComplete func(Result)
+}
+
+// WithHooks registers the given lifecycle hooks. Hooks are run synchronously, in the order they are registered,
+// and can be registered multiple times
+func WithHooks(hooks Hooks) managerOption {
+	return func(o *managerOptions) {
+		if hooks.OnSetupComplete != nil {
+			o.onSetupComplete = append(o.onSetupComplete, hooks.OnSetupComplete)
+		}
+		if hooks.OnAllStarted != nil {
+			o.onStarted = append(o.onStarted, hooks.OnAllStarted)
+		}
+		if hooks.OnShutdownStart != nil {
+			o.onShutdownStart = append(o.onShutdownStart, hooks.OnShutdownStart)
+		}
+		if hooks.OnShutdownComplete != nil {
+			o.afterShutdown = append(o.afterShutdown, hooks.OnShutdownComplete)
+		}
+	}
+}
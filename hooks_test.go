@@ -0,0 +1,45 @@
+package unixcycle_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestHooks(t *testing.T) {
+	t.Run("should call the hooks at their points of the lifecycle", func(t *testing.T) {
+		var (
+			mu     sync.Mutex
+			calls  []string
+			record = func(call string) {
+				mu.Lock()
+				defer mu.Unlock()
+				calls = append(calls, call)
+			}
+			shutdownChan = make(chan int, 1)
+			sut          = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return <-shutdownChan }),
+				unixcycle.WithHooks(unixcycle.Hooks{
+					OnSetupComplete:    func() { record("setup complete") },
+					OnAllStarted:       func(time.Duration) { record("all started"); shutdownChan <- 0 },
+					OnShutdownStart:    func() { record("shutdown start") },
+					OnShutdownComplete: func(r unixcycle.Result) { record("shutdown complete") },
+				}),
+				unixcycle.WithHooks(unixcycle.Hooks{OnShutdownStart: func() { record("deregister") }}),
+			).
+				Add("db", &testComponent{
+					setupFunc: func() error { record("setup"); return nil },
+					startFunc: func() error { select {} },
+					closeFunc: func() error { record("close"); return nil },
+				})
+		)
+
+		got := sut.Run()
+
+		assert.Equal(t, 0, got)
+		assert.Equal(t, []string{"setup", "setup complete", "all started", "shutdown start", "deregister", "close", "shutdown complete"}, calls)
+	})
+}
@@ -0,0 +1,167 @@
+package unixcycle
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+var _ Component = &DrainingHandler{}
+
+// DrainingHandler wraps an http.Handler with an in-flight request counter.
+// Once draining starts, new requests are rejected with 503 and "Connection: close"
+// while the requests already in flight are allowed to finish.
+//
+// It is drained when shutdown starts, before any component is closed.
+// When closed without having been drained, e.g. during a restart, it drains on close, so add it to the manager
+// AFTER the http server component, to close it before the server:
+//
+//	handler := unixcycle.NewDrainingHandler(mux)
+//	manager.
+//		Add("http server", server).
+//		Add("http drain", handler)
+type DrainingHandler struct {
+	handler http.Handler
+
+	mu        sync.Mutex
+	inFlight  int
+	draining  bool
+	drained   chan struct{}
+	closeOnce sync.Once
+}
+
+func NewDrainingHandler(handler http.Handler) *DrainingHandler {
+	return &DrainingHandler{
+		handler: handler,
+		drained: make(chan struct{}),
+	}
+}
+
+func (d *DrainingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	if d.draining {
+		d.mu.Unlock()
+		w.Header().Set("Connection", "close")
+		http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	d.inFlight++
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.inFlight--
+		if d.draining && d.inFlight == 0 {
+			d.closeOnce.Do(func() { close(d.drained) })
+		}
+	}()
+
+	d.handler.ServeHTTP(w, r)
+}
+
+// InFlight returns the number of requests currently being served
+func (d *DrainingHandler) InFlight() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.inFlight
+}
+
+// Drained is closed once draining has started and all in-flight requests are done
+func (d *DrainingHandler) Drained() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.drained
+}
+
+// Setup accepts requests again after the handler was drained, e.g. on a restart
+func (d *DrainingHandler) Setup() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.draining = false
+	d.drained = make(chan struct{})
+	d.closeOnce = sync.Once{}
+	return nil
+}
+
+// Drain stops accepting new requests and blocks until all in-flight requests are done or ctx is done
+func (d *DrainingHandler) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	if d.inFlight == 0 {
+		d.closeOnce.Do(func() { close(d.drained) })
+	}
+	drained := d.drained
+	d.mu.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d requests still in flight: %w", d.InFlight(), ctx.Err())
+	}
+}
+
+func (d *DrainingHandler) Start() error {
+	return nil
+}
+
+// CloseContext drains the handler until ctx is done. The manager passes a context expiring with the close timeout,
+// so in-flight requests don't keep the goroutine of a timed out Close around
+func (d *DrainingHandler) CloseContext(ctx context.Context) error {
+	return d.Drain(ctx)
+}
+
+// Close drains the handler without a deadline
+func (d *DrainingHandler) Close() error {
+	return d.CloseContext(context.Background())
+}
+
+var _ Component = &httpServerComponent{}
+
+// httpServerComponent runs an http.Server on a listener bound during setup
+type httpServerComponent struct {
+	boundListener
+	server *http.Server
+}
+
+// HTTPServer creates a component running server:
+//   - Setup listens on server.Addr (":http" when empty), so a port that is already in use fails the setup
+//   - Start serves, with TLS when server.TLSConfig is set
+//   - Close shuts the server down gracefully until the close timeout, and closes all connections once it expires
+//
+// Like the http.Server itself, the component can't be started again once closed
+func HTTPServer(server *http.Server) *httpServerComponent {
+	return &httpServerComponent{server: server}
+}
+
+func (h *httpServerComponent) Setup() error {
+	return h.listen("tcp", cmp.Or(h.server.Addr, ":http"))
+}
+
+func (h *httpServerComponent) Start() error {
+	listener := h.bound()
+
+	var err error
+	if h.server.TLSConfig != nil {
+		err = h.server.ServeTLS(listener, "", "")
+	} else {
+		err = h.server.Serve(listener)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// CloseContext shuts the server down gracefully, closing all remaining connections once ctx expires
+func (h *httpServerComponent) CloseContext(ctx context.Context) error {
+	err := h.server.Shutdown(ctx)
+	if err != nil {
+		return errors.Join(err, h.server.Close())
+	}
+	return nil
+}
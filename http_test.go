@@ -0,0 +1,147 @@
+package unixcycle_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestDrainingHandler(t *testing.T) {
+	t.Run("should reject new requests and wait for in-flight ones when draining", func(t *testing.T) {
+		var (
+			entered = make(chan struct{})
+			release = make(chan struct{})
+			sut     = unixcycle.NewDrainingHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(entered)
+				<-release
+				w.WriteHeader(http.StatusOK)
+			}))
+			inFlight = httptest.NewRecorder()
+			served   = make(chan struct{})
+			drained  = make(chan error)
+		)
+		go func() {
+			sut.ServeHTTP(inFlight, httptest.NewRequest(http.MethodGet, "/", nil))
+			close(served)
+		}()
+		<-entered
+		go func() { drained <- sut.Drain(context.Background()) }()
+		assert.Eventually(t, func() bool {
+			rec := httptest.NewRecorder()
+			sut.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			return rec.Code == http.StatusServiceUnavailable && rec.Header().Get("Connection") == "close"
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, 1, sut.InFlight())
+
+		close(release)
+
+		require.NoError(t, <-drained)
+		<-served
+		assert.Equal(t, http.StatusOK, inFlight.Code)
+		assert.Equal(t, 0, sut.InFlight())
+	})
+
+	t.Run("should return error if in-flight requests outlive the context", func(t *testing.T) {
+		var (
+			entered = make(chan struct{})
+			release = make(chan struct{})
+			sut     = unixcycle.NewDrainingHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(entered)
+				<-release
+			}))
+			ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+		)
+		defer cancel()
+		defer close(release)
+		go sut.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		<-entered
+
+		err := sut.Drain(ctx)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "1 requests still in flight")
+	})
+
+	t.Run("should stop waiting for in-flight requests on close once the context is done", func(t *testing.T) {
+		var (
+			entered = make(chan struct{})
+			release = make(chan struct{})
+			sut     = unixcycle.NewDrainingHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(entered)
+				<-release
+			}))
+			ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+		)
+		defer cancel()
+		defer close(release)
+		go sut.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		<-entered
+
+		err := sut.CloseContext(ctx)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should serve requests again after a restart", func(t *testing.T) {
+		var (
+			handler = unixcycle.NewDrainingHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			sut     = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).Add("http drain", handler)
+			handle  = sut.RunAsync()
+			rec     = httptest.NewRecorder()
+		)
+		defer func() {
+			handle.Signal(0)
+			<-handle.Done()
+		}()
+		<-sut.Started()
+
+		require.NoError(t, sut.Restart("http drain"))
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestHTTPServer(t *testing.T) {
+	t.Run("should serve until closed and cut off requests outliving the close timeout", func(t *testing.T) {
+		var (
+			entered = make(chan struct{})
+			mux     = http.NewServeMux()
+			sut     = unixcycle.HTTPServer(&http.Server{Addr: "127.0.0.1:0", Handler: mux})
+			manager = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithCloseTimeout(time.Second),
+			).
+				Add("http", sut, unixcycle.WithComponentCloseTimeout(50*time.Millisecond))
+		)
+		mux.HandleFunc("/fast", func(w http.ResponseWriter, r *http.Request) {})
+		mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+			close(entered)
+			<-r.Context().Done()
+		})
+		handle := manager.RunAsync()
+		require.Eventually(t, func() bool { return sut.Addr() != "" }, time.Second, time.Millisecond)
+
+		resp, err := http.Get("http://" + sut.Addr() + "/fast")
+		require.NoError(t, err)
+		resp.Body.Close()
+		slow := make(chan error, 1)
+		go func() {
+			_, err := http.Get("http://" + sut.Addr() + "/slow")
+			slow <- err
+		}()
+		<-entered
+		handle.Signal(0)
+		got := <-handle.Done()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Error(t, <-slow, "the slow request should be cut off")
+		assert.Error(t, got.Err, "closing took longer than the close timeout")
+	})
+}
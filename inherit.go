@@ -0,0 +1,160 @@
+package unixcycle
+
+import (
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// listenFDsStart is the first file descriptor handed to a process, after stdin, stdout and stderr
+const listenFDsStart = 3
+
+// listenerKey identifies a listener by what it was asked to listen on, so the new process of an upgrade
+// gets the same listener back when asking for the same address, even for ports like ":0"
+type listenerKey struct {
+	Network string `json:"network"`
+	Addr    string `json:"addr"`
+}
+
+// listenerExporter is an optional interface for components serving on listeners of their own,
+// handing them to the new process on Manager.Upgrade
+type listenerExporter interface {
+	Listeners() []net.Listener
+}
+
+// inheritedListeners are the listeners handed over by the process that started this one, in an upgrade or by systemd
+// socket activation. File descriptors belong to the process, so this is the only state shared by all managers
+var inheritedListeners inheritedListenerSet
+
+type inheritedListenerSet struct {
+	once      sync.Once
+	mu        sync.Mutex
+	listeners map[listenerKey]net.Listener
+	activated []net.Listener               // Passed by systemd, matched by their address
+	requested map[net.Listener]listenerKey // What the listeners returned by Listen were asked to listen on
+	ready     *os.File                     // Written to once all components are started
+}
+
+// Listen listens on addr like net.Listen, but takes over the listener for it when it was handed over to the process,
+// either by the previous process of an upgrade (see Manager.Upgrade) or by systemd socket activation (LISTEN_FDS).
+// Meant for the Setup of components serving on listeners of their own. Returning them from a Listeners() []net.Listener
+// method hands them to the new process on Manager.Upgrade, like the ones of Listener and HTTPServer
+func Listen(network, addr string) (net.Listener, error) {
+	if listener, ok := InheritedListener(network, addr); ok {
+		return listener, nil
+	}
+
+	listener, err := net.Listen(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	inherited := loadedInheritedListeners()
+	defer inherited.mu.Unlock()
+
+	inherited.requested[listener] = listenerKey{Network: network, Addr: addr}
+	return listener, nil
+}
+
+// InheritedListener returns the listener for network and addr handed over to the process, if there is one, see Listen.
+// Listeners passed by systemd match when they listen on the same address, e.g. ":8080" matches "[::]:8080".
+// Every listener is only returned once
+func InheritedListener(network, addr string) (net.Listener, bool) {
+	inherited := loadedInheritedListeners()
+	defer inherited.mu.Unlock()
+
+	key := listenerKey{Network: network, Addr: addr}
+	listener, ok := inherited.listeners[key]
+	if ok {
+		delete(inherited.listeners, key)
+	} else if i := slices.IndexFunc(inherited.activated, func(l net.Listener) bool { return listensOn(l, network, addr) }); i >= 0 {
+		listener, ok = inherited.activated[i], true
+		inherited.activated = slices.Delete(inherited.activated, i, i+1)
+	}
+	if ok {
+		inherited.requested[listener] = key
+	}
+	return listener, ok
+}
+
+// exportKey returns what listener was asked to listen on, falling back to the address it listens on
+func exportKey(listener net.Listener) listenerKey {
+	inherited := loadedInheritedListeners()
+	defer inherited.mu.Unlock()
+
+	if key, ok := inherited.requested[listener]; ok {
+		return key
+	}
+	return listenerKey{Network: listener.Addr().Network(), Addr: listener.Addr().String()}
+}
+
+// loadedInheritedListeners returns the inherited listeners locked, loading them on first use
+func loadedInheritedListeners() *inheritedListenerSet {
+	inherited := &inheritedListeners
+	inherited.once.Do(loadInheritedListeners)
+	inherited.mu.Lock()
+	return inherited
+}
+
+// loadInheritedListeners picks up the file descriptors from the environment. The variables are unset,
+// so they don't leak into other child processes
+func loadInheritedListeners() {
+	inherited := &inheritedListeners
+	inherited.listeners = map[listenerKey]net.Listener{}
+	inherited.requested = map[net.Listener]listenerKey{}
+
+	if !loadUpgradeListeners() {
+		loadActivatedListeners()
+	}
+}
+
+// loadActivatedListeners picks up the listeners passed by systemd socket activation, following sd_listen_fds
+func loadActivatedListeners() {
+	pid, fds := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS")
+	_ = os.Unsetenv("LISTEN_PID")
+	_ = os.Unsetenv("LISTEN_FDS")
+	_ = os.Unsetenv("LISTEN_FDNAMES")
+	if pid != strconv.Itoa(os.Getpid()) {
+		return // Meant for another process
+	}
+
+	n, err := strconv.Atoi(fds)
+	if err != nil {
+		return
+	}
+	for fd := listenFDsStart; fd < listenFDsStart+n; fd++ {
+		f := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		listener, err := net.FileListener(f)
+		f.Close()
+		if err == nil {
+			inheritedListeners.activated = append(inheritedListeners.activated, listener)
+		}
+	}
+}
+
+// listensOn reports whether listener listens on addr, with an unspecified host in addr matching any
+func listensOn(listener net.Listener, network, addr string) bool {
+	actual := listener.Addr()
+	if !strings.HasPrefix(network, actual.Network()) && !strings.HasPrefix(actual.Network(), network) {
+		return false // "tcp" matches "tcp4" and "tcp6" either way
+	}
+	if actual.Network() == "unix" {
+		return actual.String() == addr
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	actualHost, actualPort, err := net.SplitHostPort(actual.String())
+	if err != nil || port != actualPort {
+		return false
+	}
+	if host == "" {
+		return true
+	}
+	ip, actualIP := net.ParseIP(host), net.ParseIP(actualHost)
+	return host == actualHost || (ip != nil && ip.Equal(actualIP))
+}
@@ -0,0 +1,130 @@
+//go:build !windows
+
+package unixcycle_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+// activatedChildEnv makes TestListenActivatedChild act as a process started by systemd socket activation, listening on its value
+const activatedChildEnv = "UNIXCYCLE_TEST_ACTIVATED_CHILD"
+
+func TestListen(t *testing.T) {
+	t.Run("should listen when no listener was handed over", func(t *testing.T) {
+		sut, err := unixcycle.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer sut.Close()
+
+		_, inherited := unixcycle.InheritedListener("tcp", "127.0.0.1:0")
+
+		assert.NotEmpty(t, sut.Addr().String())
+		assert.False(t, inherited)
+	})
+
+	t.Run("should take over the listeners passed by systemd socket activation", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		f, err := listener.(*net.TCPListener).File()
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		_, port, _ := net.SplitHostPort(addr)
+
+		cmd := exec.Command(os.Args[0], "-test.run=^TestListenActivatedChild$")
+		cmd.Env = append(os.Environ(), activatedChildEnv+"=:"+port, "LISTEN_FDS=1")
+		cmd.ExtraFiles = []*os.File{f}
+		require.NoError(t, cmd.Start())
+		f.Close()
+		listener.Close() // Only the child has it now
+		defer func() { _ = cmd.Wait() }()
+
+		assert.Equal(t, "activated", get(t, addr))
+	})
+
+	t.Run("should hand listeners exported by components to the new process of an upgrade", func(t *testing.T) {
+		t.Setenv(upgradeChildEnv, "1")
+		var (
+			sut = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithUpgradeCommand(os.Args[0], "-test.run=^TestUpgradeChild$"),
+			)
+			component = &exportingComponent{}
+			done      = make(chan int)
+		)
+		sut.Add("server", component)
+		go func() { done <- sut.Run() }()
+		<-sut.Started()
+		addr := component.listener.Addr().String()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		require.NoError(t, sut.Upgrade(ctx))
+
+		assert.Equal(t, 0, <-done)
+		assert.Equal(t, "new", get(t, addr), "the new process should serve on the same port")
+	})
+}
+
+// TestListenActivatedChild is the process started by TestListen with a listener passed like systemd does.
+// It serves a single request and exits
+func TestListenActivatedChild(t *testing.T) {
+	addr := os.Getenv(activatedChildEnv)
+	if addr == "" {
+		t.Skip("only run as the process started by TestListen")
+	}
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid())) // Only known once started
+
+	listener, ok := unixcycle.InheritedListener("tcp", addr)
+	if !ok {
+		os.Exit(1)
+	}
+	served := make(chan struct{})
+	go func() {
+		_ = http.Serve(listener, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Connection", "close")
+			_, _ = io.WriteString(w, "activated")
+			w.(http.Flusher).Flush()
+			close(served)
+		}))
+	}()
+	select {
+	case <-served:
+	case <-time.After(10 * time.Second):
+	}
+
+	os.Exit(0) // Don't print the test summary into the output of TestListen
+}
+
+// exportingComponent serves on a listener of its own, see unixcycle.Listen
+type exportingComponent struct {
+	listener net.Listener
+}
+
+func (e *exportingComponent) Setup() (err error) {
+	e.listener, err = unixcycle.Listen("tcp", upgradeAddr)
+	return err
+}
+
+func (e *exportingComponent) Start() error {
+	_ = serveText("old")(e.listener)
+	return nil
+}
+
+func (e *exportingComponent) Close() error {
+	return e.listener.Close()
+}
+
+func (e *exportingComponent) Listeners() []net.Listener {
+	return []net.Listener{e.listener}
+}
@@ -0,0 +1,85 @@
+package unixcycle
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+)
+
+var errorType = reflect.TypeFor[error]()
+
+// Provide registers the component returned by constructor, passing it the previously added components it takes
+// as parameters, matched by type:
+//
+//	manager.
+//		Add("db", unixcycle.Make[Database](NewDatabase)).
+//		Provide("api", func(db *Database) (*API, error) { return NewAPI(db) })
+//
+// constructor must be a function returning a component, optionally followed by an error.
+// The component depends on the components passed to it, see DependsOn.
+// A parameter matching no or several components, or constructor failing, is handled like an invalid Add
+func (m *Manager) Provide(name string, constructor any, options ...componentOption) *Manager {
+	component, dependencies, err := m.construct(constructor)
+	if err != nil {
+		err = fmt.Errorf("constructing component %q: %w", name, err)
+		m.logError(CodeComponentInvalid, fmt.Sprintf("Invalid component: %v", err), slog.String("component_name", name))
+		m.addErrs = append(m.addErrs, err)
+		return m
+	}
+
+	return m.Add(name, component, append([]componentOption{DependsOn(dependencies...)}, options...)...)
+}
+
+// construct calls constructor with the components its parameters ask for, returning the names of those components
+func (m *Manager) construct(constructor any) (Component, []string, error) {
+	fn := reflect.ValueOf(constructor)
+	if fn.Kind() != reflect.Func || fn.IsNil() {
+		return nil, nil, fmt.Errorf("constructor is a %T, not a function", constructor)
+	}
+	typ := fn.Type()
+	if typ.NumOut() == 0 || typ.NumOut() > 2 || (typ.NumOut() == 2 && typ.Out(1) != errorType) {
+		return nil, nil, errors.New("constructor must return a component, optionally followed by an error")
+	}
+
+	var (
+		args         = make([]reflect.Value, typ.NumIn())
+		dependencies = make([]string, typ.NumIn())
+	)
+	for i := range typ.NumIn() {
+		s, err := m.componentOfType(typ.In(i))
+		if err != nil {
+			return nil, nil, err
+		}
+		args[i], dependencies[i] = reflect.ValueOf(s.Component), s.name
+	}
+
+	out := fn.Call(args)
+	if len(out) == 2 && !out[1].IsNil() {
+		return nil, nil, out[1].Interface().(error)
+	}
+	component, ok := out[0].Interface().(Component)
+	if !ok {
+		return nil, nil, fmt.Errorf("constructor returned a %s, which does not implement Start", typ.Out(0))
+	}
+	return component, dependencies, nil
+}
+
+// componentOfType returns the only added component assignable to typ
+func (m *Manager) componentOfType(typ reflect.Type) (namedComponent, error) {
+	var matches []namedComponent
+	for _, s := range m.components {
+		if reflect.TypeOf(s.Component).AssignableTo(typ) {
+			matches = append(matches, s)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return namedComponent{}, fmt.Errorf("no component of type %s was added before", typ)
+	case 1:
+		return matches[0], nil
+	default:
+		return namedComponent{}, fmt.Errorf("%d components of type %s were added before, %q and %q at least", len(matches), typ, matches[0].name, matches[1].name)
+	}
+}
@@ -0,0 +1,82 @@
+package unixcycle_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+type database struct{ setUp bool }
+
+func (d *database) Setup() error { d.setUp = true; return nil }
+func (d *database) Start() error { return nil }
+
+type api struct {
+	db           *database
+	dbSetUpFirst bool
+}
+
+func (a *api) Setup() error { a.dbSetUpFirst = a.db.setUp; return nil }
+func (a *api) Start() error { return nil }
+
+func TestProvide(t *testing.T) {
+	t.Run("should pass previously added components to the constructor and depend on them", func(t *testing.T) {
+		var (
+			built *api
+			db    = &database{}
+			sut   = unixcycle.NewManager(unixcycle.WithLifetime(func() int { return 0 })).
+				Add("db", db).
+				Provide("api", func(db *database) (*api, error) { built = &api{db: db}; return built, nil })
+		)
+
+		got := sut.RunWithResult()
+
+		require.NoError(t, got.Err)
+		assert.Same(t, db, built.db)
+		assert.True(t, built.dbSetUpFirst, "should be set up after its dependencies")
+	})
+
+	t.Run("should fail the run when a dependency was not added", func(t *testing.T) {
+		sut := unixcycle.NewManager(unixcycle.WithLifetime(func() int { return 0 })).
+			Provide("api", func(db *database) *api { return &api{db: db} })
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, unixcycle.PhaseAdd, got.FailedPhase)
+		assert.ErrorContains(t, got.Err, `constructing component "api": no component of type *unixcycle_test.database was added before`)
+	})
+
+	t.Run("should fail the run when a dependency is ambiguous", func(t *testing.T) {
+		sut := unixcycle.NewManager(unixcycle.WithLifetime(func() int { return 0 })).
+			Add("primary", &database{}).
+			Add("replica", &database{}).
+			Provide("api", func(db *database) *api { return &api{db: db} })
+
+		got := sut.RunWithResult()
+
+		assert.ErrorContains(t, got.Err, `2 components of type *unixcycle_test.database were added before, "primary" and "replica" at least`)
+	})
+
+	t.Run("should fail the run when the constructor fails", func(t *testing.T) {
+		sut := unixcycle.NewManager(unixcycle.WithLifetime(func() int { return 0 })).
+			Provide("api", func() (*api, error) { return nil, assert.AnError })
+
+		got := sut.RunWithResult()
+
+		assert.ErrorIs(t, got.Err, assert.AnError)
+	})
+
+	t.Run("should accept dependencies by interface", func(t *testing.T) {
+		var (
+			got any
+			sut = unixcycle.NewManager(unixcycle.WithLifetime(func() int { return 0 })).
+				Add("db", &database{}).
+				Provide("api", func(c interface{ Setup() error }) *api { got = c; return &api{db: &database{}} })
+		)
+
+		assert.NoError(t, sut.RunWithResult().Err)
+		assert.IsType(t, &database{}, got)
+	})
+}
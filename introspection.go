@@ -0,0 +1,103 @@
+package unixcycle
+
+import (
+	"iter"
+	"slices"
+	"time"
+)
+
+// ComponentInfo describes a registered component
+type ComponentInfo struct {
+	Name string
+	// Interfaces lists the lifecycle methods the component implements, e.g. "Setup", "Run", "Start", "Checkpoint", "Close"
+	Interfaces []string
+	Tags       []string
+	State      ComponentState
+	// Transitions lists the states the component entered and when, oldest first. Only the most recent ones are kept
+	Transitions []StateTransition
+	Timings     ComponentTimings
+	// LastError is the error the component last failed with, nil if it never failed
+	LastError error
+}
+
+// ComponentTimings holds how long the lifecycle methods of a component took, zero for those not called (yet)
+type ComponentTimings struct {
+	Setup      time.Duration
+	Drain      time.Duration
+	Checkpoint time.Duration
+	Close      time.Duration
+	// Uptime is how long the component has been running, or was running until it stopped or started closing
+	Uptime time.Duration
+}
+
+// Components yields every registered component in the order they are set up and started, which respects their
+// dependencies and priorities. Until the manager runs, that is still the order they were added in
+func (m *Manager) Components() iter.Seq[ComponentInfo] {
+	return func(yield func(ComponentInfo) bool) {
+		m.mu.Lock()
+		infos := make([]ComponentInfo, 0, len(m.components))
+		for _, s := range m.components {
+			timings := s.status.timings
+			if s.status.running() {
+				timings.Uptime = time.Since(s.status.startedAt)
+			}
+			infos = append(infos, ComponentInfo{
+				Name:        s.name,
+				Interfaces:  implementedInterfaces(s.Component),
+				Tags:        slices.Clone(s.options.tags),
+				State:       s.status.state,
+				Transitions: slices.Clone(s.status.transitions),
+				Timings:     timings,
+				LastError:   s.status.lastErr,
+			})
+		}
+		m.mu.Unlock() // Don't hold the lock while yielding, the caller may call back into the manager
+
+		for _, info := range infos {
+			if !yield(info) {
+				return
+			}
+		}
+	}
+}
+
+func implementedInterfaces(c Component) []string {
+	var interfaces []string
+	if _, ok := c.(setupable); ok {
+		interfaces = append(interfaces, "Setup")
+	}
+	if _, ok := c.(runnable); ok {
+		interfaces = append(interfaces, "Run")
+	}
+	if _, ok := c.(startable); ok {
+		interfaces = append(interfaces, "Start")
+	}
+	if _, ok := c.(readyable); ok {
+		interfaces = append(interfaces, "Ready")
+	}
+	if _, ok := c.(drainable); ok {
+		interfaces = append(interfaces, "Drain")
+	}
+	if _, ok := c.(checkpointable); ok {
+		interfaces = append(interfaces, "Checkpoint")
+	}
+	if _, ok := c.(contextClosable); ok {
+		interfaces = append(interfaces, "CloseContext")
+	} else if _, ok := c.(closable); ok {
+		interfaces = append(interfaces, "Close")
+	}
+	if _, ok := c.(reloadable); ok {
+		interfaces = append(interfaces, "Reload")
+	}
+	if _, ok := c.(healther); ok {
+		interfaces = append(interfaces, "Health")
+	}
+	if _, ok := c.(StateSaver); ok {
+		interfaces = append(interfaces, "StateSaver")
+	}
+	if _, ok := c.(loggerAware); ok {
+		interfaces = append(interfaces, "SetLogger")
+	}
+
+	return interfaces
+}
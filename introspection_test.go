@@ -0,0 +1,102 @@
+package unixcycle_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestComponents(t *testing.T) {
+	t.Run("should yield registered components with interfaces, tags and state", func(t *testing.T) {
+		var (
+			comp = &testComponent{}
+			sut  = unixcycle.NewManager().
+				Add("full", unixcycle.Make[testComponent](comp), unixcycle.Tags("db", "critical")).
+				Add("closer", unixcycle.Closer(func() error { return nil }))
+		)
+
+		got := slices.Collect(sut.Components())
+		for i := range got {
+			require.Len(t, got[i].Transitions, 1)
+			assert.Equal(t, unixcycle.StateRegistered, got[i].Transitions[0].State)
+			got[i].Transitions = nil
+		}
+
+		assert.Equal(t, []unixcycle.ComponentInfo{
+			{Name: "full", Interfaces: []string{"Setup", "Start", "Close"}, Tags: []string{"db", "critical"}, State: unixcycle.StateRegistered},
+			{Name: "closer", Interfaces: []string{"Start", "Close"}, State: unixcycle.StateRegistered},
+		}, got)
+	})
+
+	t.Run("should report how long the lifecycle methods took", func(t *testing.T) {
+		var (
+			stop  = make(chan struct{})
+			sleep = func() error { time.Sleep(5 * time.Millisecond); return nil }
+			comp  = &testComponent{
+				setupFunc: sleep,
+				startFunc: func() error { <-stop; return nil },
+				closeFunc: func() error { close(stop); return sleep() },
+			}
+			sut = unixcycle.NewManager(unixcycle.WithLifetime(func() int { time.Sleep(5 * time.Millisecond); return 0 })).
+				Add("db", unixcycle.Make[testComponent](comp))
+		)
+
+		sut.Run()
+		got := slices.Collect(sut.Components())[0].Timings
+
+		assert.GreaterOrEqual(t, got.Setup, 5*time.Millisecond)
+		assert.GreaterOrEqual(t, got.Uptime, 5*time.Millisecond)
+		assert.GreaterOrEqual(t, got.Close, 5*time.Millisecond)
+		assert.Zero(t, got.Drain)
+		assert.Zero(t, got.Checkpoint)
+	})
+
+	t.Run("should record every state transition with its time", func(t *testing.T) {
+		var (
+			before = time.Now()
+			sut    = unixcycle.NewManager(unixcycle.WithLifetime(func() int { return 0 })).
+				Add("server", &blockingDrainComponent{stop: make(chan struct{})})
+		)
+
+		sut.Run()
+		got := slices.Collect(sut.Components())[0].Transitions
+
+		var states []unixcycle.ComponentState
+		for i, transition := range got {
+			states = append(states, transition.State)
+			assert.False(t, transition.Time.Before(before))
+			if i > 0 {
+				assert.False(t, transition.Time.Before(got[i-1].Time))
+			}
+		}
+		assert.Equal(t, []unixcycle.ComponentState{
+			unixcycle.StateRegistered,
+			unixcycle.StateRunning,
+			unixcycle.StateDraining,
+			unixcycle.StateClosing,
+			unixcycle.StateClosed,
+		}, states)
+	})
+}
+
+// blockingDrainComponent runs until drained
+type blockingDrainComponent struct {
+	stop chan struct{}
+}
+
+func (c *blockingDrainComponent) Start() error {
+	<-c.stop
+	return nil
+}
+
+func (c *blockingDrainComponent) Drain(ctx context.Context) error {
+	close(c.stop)
+	return nil
+}
+
+func (c *blockingDrainComponent) Close() error { return nil }
@@ -0,0 +1,151 @@
+package unixcycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// LeaderGate runs a leader election, e.g. on an etcd or Consul lock or a Kubernetes lease, for the components added
+// with LeaderOnly. See WithLeaderGate
+type LeaderGate interface {
+	// Campaign takes part in the election until ctx is done, calling leading with true once leadership is acquired
+	// and with false once it is lost. leading blocks until the leader-only components are started or closed,
+	// so leadership should be released only after it returned. Returning before ctx is done counts as losing leadership,
+	// and an error shuts the manager down
+	Campaign(ctx context.Context, leading func(leader bool)) error
+}
+
+// WithLeaderGate runs the components added with LeaderOnly only while gate reports leadership. They are set up and started
+// once leadership is acquired, and drained and closed once it is lost, while the other components keep running.
+// The election begins once all other components are started, and ends after all components are closed on shutdown
+func WithLeaderGate(gate LeaderGate) managerOption {
+	return func(o *managerOptions) {
+		o.leaderGate = gate
+	}
+}
+
+// LeaderOnly runs a component only while the manager is the leader, see WithLeaderGate.
+// Until then it is in StateStandby. Only other leader-only components can depend on it
+func LeaderOnly() componentOption {
+	return func(o *componentOptions) {
+		o.leaderOnly = true
+	}
+}
+
+// validateLeaderOnly checks the leader-only components before starting, see checkLeaderOnly
+func (m *Manager) validateLeaderOnly() error {
+	err := m.checkLeaderOnly()
+	if err != nil {
+		m.logError(CodeDependencyInvalid, fmt.Sprintf("Invalid leader-only components: %v", err))
+	}
+	return err
+}
+
+// checkLeaderOnly checks that there is a leader gate for the leader-only components,
+// and that no other component depends on them, as it would be started without them
+func (m *Manager) checkLeaderOnly() error {
+	leaderOnly := make(map[string]bool, len(m.components))
+	for _, s := range m.components {
+		leaderOnly[s.name] = s.options.leaderOnly
+		if s.options.leaderOnly && m.leaderGate == nil {
+			return fmt.Errorf("component %q is leader-only, but there is no leader gate", s.name)
+		}
+	}
+	for _, s := range m.components {
+		for _, dep := range s.options.dependsOn {
+			if leaderOnly[dep] && !s.options.leaderOnly {
+				return fmt.Errorf("component %q depends on component %q which is leader-only", s.name, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// awaitsLeadership reports whether s is leader-only and the manager is not the leader. The caller holds the lifecycle lock
+func (m *Manager) awaitsLeadership(s namedComponent) bool {
+	return s.options.leaderOnly && !m.leader
+}
+
+// campaign takes part in the election of the leader gate, until stop is called
+func (m *Manager) campaign() (stop func()) {
+	if m.leaderGate == nil {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := m.leaderGate.Campaign(ctx, m.leading)
+		if ctx.Err() != nil {
+			return
+		}
+		m.leading(false)
+		if err != nil {
+			m.logError(CodeLeaderElectionFailed, fmt.Sprintf("Failure during leader election: %v", err))
+			m.abort(fmt.Errorf("leader election: %w", err))
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done // Hold on to the leadership until all components are closed
+	}
+}
+
+// leading starts the leader-only components once leadership is acquired, and closes them once it is lost
+func (m *Manager) leading(leader bool) {
+	select {
+	case <-m.started:
+	case <-m.shutdownCtx.Done():
+		return // Shutdown closes them
+	}
+
+	m.lifecycle.Lock()
+	defer m.lifecycle.Unlock()
+	if m.shutdownCtx.Err() != nil || leader == m.leader {
+		return
+	}
+	m.leader = leader
+
+	components := slices.DeleteFunc(slices.Clone(m.components), func(s namedComponent) bool { return !s.options.leaderOnly })
+	if leader {
+		m.logInfo(CodeLeaderAcquired, fmt.Sprintf("Acquired leadership, starting %d leader-only components", len(components)))
+		m.startLeaderOnly(components)
+	} else {
+		m.logInfo(CodeLeaderLost, fmt.Sprintf("Lost leadership, closing %d leader-only components", len(components)))
+		m.stopLeaderOnly(components)
+	}
+}
+
+// startLeaderOnly sets up and starts the leader-only components. A failed setup shuts the manager down
+func (m *Manager) startLeaderOnly(components []namedComponent) {
+	for _, s := range components {
+		if err := m.setupComponent(s); err != nil {
+			m.abort(fmt.Errorf("setting up leader-only components: %w", err))
+			return
+		}
+	}
+	for _, s := range components {
+		m.launch(s)
+	}
+}
+
+// stopLeaderOnly drains and closes the leader-only components, in the reverse order they were started in.
+// A component that fails to close shuts the manager down, as it might still act like the leader
+func (m *Manager) stopLeaderOnly(components []namedComponent) {
+	var errs []error
+	for _, s := range slices.Backward(components) {
+		_ = m.drainComponent(s) // Failures are logged, it is closed anyway
+		if err := m.stopOne(s); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		m.setState(s, StateStandby, "", nil)
+	}
+	if err := errors.Join(errs...); err != nil {
+		m.abort(fmt.Errorf("closing leader-only components: %w", err))
+	}
+}
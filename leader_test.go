@@ -0,0 +1,143 @@
+package unixcycle_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestLeaderGate(t *testing.T) {
+	t.Run("should only run leader-only components while leading", func(t *testing.T) {
+		var (
+			gate      = newFakeLeaderGate()
+			scheduler = &restartableComponent{}
+			api       = &restartableComponent{}
+			shutdown  = make(chan int, 1)
+			sut       = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return <-shutdown }),
+				unixcycle.WithLeaderGate(gate),
+			)
+			done  = make(chan int)
+			state = func(name string) unixcycle.ComponentState {
+				for c := range sut.Components() {
+					if c.Name == name {
+						return c.State
+					}
+				}
+				return ""
+			}
+		)
+		sut.
+			Add("api", api).
+			Add("scheduler", scheduler, unixcycle.LeaderOnly())
+		go func() { done <- sut.Run() }()
+		<-sut.Started()
+
+		assert.Equal(t, unixcycle.StateStandby, state("scheduler"))
+		assert.Equal(t, int32(0), scheduler.setups.Load())
+
+		gate.lead(true)
+		assert.Equal(t, unixcycle.StateRunning, state("scheduler"))
+		assert.Equal(t, int32(1), scheduler.setups.Load())
+
+		gate.lead(false)
+		assert.Equal(t, unixcycle.StateStandby, state("scheduler"))
+		assert.Equal(t, unixcycle.StateRunning, state("api"), "the other components should keep running")
+
+		gate.lead(true)
+		assert.Equal(t, unixcycle.StateRunning, state("scheduler"))
+		assert.Equal(t, int32(2), scheduler.setups.Load())
+
+		shutdown <- 0
+		assert.Equal(t, 0, <-done)
+		assert.Equal(t, unixcycle.StateClosed, state("scheduler"))
+		assert.Equal(t, int32(1), api.setups.Load())
+	})
+
+	t.Run("should shut down when the election fails", func(t *testing.T) {
+		var (
+			gate = newFakeLeaderGate()
+			sut  = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithLeaderGate(gate),
+			)
+			done = make(chan unixcycle.Result)
+		)
+		sut.Add("scheduler", &restartableComponent{}, unixcycle.LeaderOnly())
+		go func() { done <- sut.RunWithResult() }()
+		<-sut.Started()
+		gate.lead(true)
+
+		gate.fail <- assert.AnError
+
+		result := <-done
+		assert.Equal(t, int(syscall.SIGABRT), result.ExitCode)
+		assert.ErrorIs(t, result.Err, assert.AnError)
+	})
+
+	t.Run("should fail for leader-only components without a leader gate", func(t *testing.T) {
+		sut := unixcycle.NewManager(unixcycle.WithLifetime(func() int { return 0 }))
+		sut.Add("scheduler", &restartableComponent{}, unixcycle.LeaderOnly())
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, unixcycle.PhaseDependencies, got.FailedPhase)
+		assert.ErrorContains(t, got.Err, `component "scheduler" is leader-only, but there is no leader gate`)
+	})
+
+	t.Run("should fail when a component depends on a leader-only component", func(t *testing.T) {
+		sut := unixcycle.NewManager(
+			unixcycle.WithLifetime(func() int { return 0 }),
+			unixcycle.WithLeaderGate(newFakeLeaderGate()),
+		)
+		sut.
+			Add("scheduler", &restartableComponent{}, unixcycle.LeaderOnly()).
+			Add("api", &restartableComponent{}, unixcycle.DependsOn("scheduler"))
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, unixcycle.PhaseDependencies, got.FailedPhase)
+		assert.ErrorContains(t, got.Err, `component "api" depends on component "scheduler" which is leader-only`)
+	})
+}
+
+// fakeLeaderGate reports the leadership it is told, see lead
+type fakeLeaderGate struct {
+	changes chan bool
+	handled chan struct{}
+	fail    chan error
+}
+
+func newFakeLeaderGate() *fakeLeaderGate {
+	return &fakeLeaderGate{changes: make(chan bool), handled: make(chan struct{}), fail: make(chan error)}
+}
+
+func (g *fakeLeaderGate) Campaign(ctx context.Context, leading func(leader bool)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-g.fail:
+			return err
+		case leader := <-g.changes:
+			leading(leader)
+			g.handled <- struct{}{}
+		}
+	}
+}
+
+// lead reports leadership and waits for the manager to act on it
+func (g *fakeLeaderGate) lead(leader bool) {
+	g.changes <- leader
+	select {
+	case <-g.handled:
+	case <-time.After(5 * time.Second):
+		panic("leadership change was not handled")
+	}
+}
+
+var _ unixcycle.LeaderGate = &fakeLeaderGate{}
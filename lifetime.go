@@ -3,16 +3,21 @@ package unixcycle
 import (
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 )
 
 type TerminationSignal func() int
 
+// interrupted is the signal InterruptSignal last received, so it can be forwarded as received
+var interrupted atomic.Int32
+
 func InterruptSignal() int {
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
 
-	<-signals
+	sig := <-signals
+	interrupted.Store(int32(sig.(syscall.Signal)))
 
 	return 0
 }
@@ -0,0 +1,105 @@
+package unixcycle
+
+import (
+	"errors"
+	"net"
+	"sync"
+)
+
+// boundListener is a listener bound during setup, shared by the components serving on one.
+// Binding early makes port conflicts fail the setup, before anything is started
+type boundListener struct {
+	mu       sync.Mutex
+	listener net.Listener
+	key      listenerKey
+}
+
+// listen listens on addr, or takes over the listener for it handed over to the process, see Listen
+func (b *boundListener) listen(network, addr string) error {
+	listener, err := Listen(network, addr)
+	if err != nil {
+		return err
+	}
+	b.set(listener, listenerKey{Network: network, Addr: addr})
+	return nil
+}
+
+// inherit takes over the listener for addr handed over to the process, reporting whether there was one
+func (b *boundListener) inherit(network, addr string) bool {
+	listener, ok := InheritedListener(network, addr)
+	if ok {
+		b.set(listener, listenerKey{Network: network, Addr: addr})
+	}
+	return ok
+}
+
+func (b *boundListener) set(listener net.Listener, key listenerKey) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.listener = listener
+	b.key = key
+}
+
+func (b *boundListener) bound() net.Listener {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.listener
+}
+
+// handover returns the listener and what it was asked to listen on, for the next process of an upgrade
+func (b *boundListener) handover() (listenerKey, net.Listener) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.key, b.listener
+}
+
+// Addr returns the address the component listens on, useful when listening on port 0.
+// Empty before the component is set up
+func (b *boundListener) Addr() string {
+	listener := b.bound()
+	if listener == nil {
+		return ""
+	}
+	return listener.Addr().String()
+}
+
+var _ Component = &listenerComponent{}
+
+// listenerComponent hands a listener bound during setup to a serve function
+type listenerComponent struct {
+	boundListener
+	network string
+	addr    string
+	serve   func(listener net.Listener) error
+}
+
+// Listener creates a component for any server that accepts connections from a net.Listener:
+//   - Setup listens on addr, so a port that is already in use fails the setup and readiness probes can connect early
+//   - Start hands the listener to serve. Errors from the listener being closed count as a clean stop
+//   - Close closes the listener, which should make serve return
+func Listener(network, addr string, serve func(listener net.Listener) error) *listenerComponent {
+	return &listenerComponent{network: network, addr: addr, serve: serve}
+}
+
+func (l *listenerComponent) Setup() error {
+	return l.listen(l.network, l.addr)
+}
+
+func (l *listenerComponent) Start() error {
+	err := l.serve(l.bound())
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+func (l *listenerComponent) Close() error {
+	err := l.bound().Close()
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package unixcycle_test
+
+import (
+	"bufio"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestListener(t *testing.T) {
+	t.Run("should serve on the listener bound during setup until closed", func(t *testing.T) {
+		var (
+			sut = unixcycle.Listener("tcp", "127.0.0.1:0", func(listener net.Listener) error {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return err
+					}
+					_, _ = conn.Write([]byte("hello\n"))
+					conn.Close()
+				}
+			})
+			result = make(chan error, 1)
+		)
+		require.NoError(t, sut.Setup())
+		go func() { result <- sut.Start() }()
+
+		conn, err := net.Dial("tcp", sut.Addr())
+		require.NoError(t, err)
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		conn.Close()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "hello\n", line)
+		assert.NoError(t, sut.Close())
+		assert.NoError(t, <-result, "closing the listener should count as a clean stop")
+	})
+
+	t.Run("should fail setup when the address is in use", func(t *testing.T) {
+		taken, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer taken.Close()
+
+		sut := unixcycle.Listener("tcp", taken.Addr().String(), func(net.Listener) error { return nil })
+
+		assert.Error(t, sut.Setup())
+	})
+}
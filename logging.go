@@ -0,0 +1,210 @@
+package unixcycle
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// componentNameKey is the attribute key the manager logs component names under, see LogKeys
+const componentNameKey = "component_name"
+
+// LogKeys are the attribute keys of the manager's log records. Empty keys keep their default
+type LogKeys struct {
+	// Component is the key of the component name. Default is "component_name"
+	Component string
+	// Code is the key of the event code, see Code. Default is "code"
+	Code string
+}
+
+// WithLogPrefix sets the prefix of every log message of the manager, e.g. "" to drop it
+// Default is "[UnixCycle] "
+func WithLogPrefix(prefix string) managerOption {
+	return func(o *managerOptions) {
+		o.logPrefix = prefix
+	}
+}
+
+// WithLogKeys renames the attribute keys of the manager's log records, to fit an existing log schema
+func WithLogKeys(keys LogKeys) managerOption {
+	return func(o *managerOptions) {
+		o.logKeys.Component = cmp.Or(keys.Component, o.logKeys.Component)
+		o.logKeys.Code = cmp.Or(keys.Code, o.logKeys.Code)
+	}
+}
+
+// WithoutComponentNamesInMessages only logs component names as an attribute, not as part of the message,
+// e.g. `Closing component` instead of `Closing component "db"`. Useful to group log records by message
+func WithoutComponentNamesInMessages() managerOption {
+	return func(o *managerOptions) {
+		o.namesInMessages = false
+	}
+}
+
+// SetLogLevel changes the minimum level of the default logger while running, e.g. to slog.LevelDebug to diagnose
+// a live service. See WithLogLevel. Fails with WithLogger, as the level of that logger is up to its handler
+func (m *Manager) SetLogLevel(level slog.Level) error {
+	if m.logLevel == nil {
+		return errors.New("the log level of a logger set with WithLogger can not be changed")
+	}
+
+	m.logLevel.Set(level)
+	return nil
+}
+
+// log writes a log record with the prefix, attribute keys and component names as configured
+func (m *Manager) log(level slog.Level, code Code, msg string, attrs []any) {
+	for i, attr := range attrs {
+		attr, ok := attr.(slog.Attr)
+		if !ok || attr.Key != componentNameKey {
+			continue
+		}
+
+		if !m.namesInMessages {
+			msg = strings.Replace(msg, " "+strconv.Quote(attr.Value.String()), "", 1)
+		}
+		attr.Key = m.logKeys.Component
+		attrs[i] = attr
+	}
+
+	m.logger.Log(context.Background(), level, m.logPrefix+msg, append(attrs, slog.String(m.logKeys.Code, string(code)))...)
+}
+
+// Logger is a minimal leveled key-value logger, so logging libraries other than slog can receive the manager's logs,
+// see LoggerHandler. *slog.Logger implements it, and LoggerFuncs adapts the methods of other libraries.
+// The unixcyclezap and unixcyclelogrus modules adapt zap and logrus
+type Logger interface {
+	Debug(msg string, keysAndValues ...any)
+	Info(msg string, keysAndValues ...any)
+	Warn(msg string, keysAndValues ...any)
+	Error(msg string, keysAndValues ...any)
+}
+
+// LoggerFuncs implements Logger with a function per level. Nil functions drop the messages of their level.
+// The key-value methods of zap's SugaredLogger fit as they are:
+//
+//	sugar := zapLogger.Sugar()
+//	unixcycle.LoggerFuncs{DebugFunc: sugar.Debugw, InfoFunc: sugar.Infow, WarnFunc: sugar.Warnw, ErrorFunc: sugar.Errorw}
+//
+// Libraries logging fields instead, like logrus, need a small function per level, see KeyValues
+type LoggerFuncs struct {
+	DebugFunc func(msg string, keysAndValues ...any)
+	InfoFunc  func(msg string, keysAndValues ...any)
+	WarnFunc  func(msg string, keysAndValues ...any)
+	ErrorFunc func(msg string, keysAndValues ...any)
+}
+
+func (l LoggerFuncs) Debug(msg string, keysAndValues ...any) {
+	callLogFunc(l.DebugFunc, msg, keysAndValues)
+}
+
+func (l LoggerFuncs) Info(msg string, keysAndValues ...any) {
+	callLogFunc(l.InfoFunc, msg, keysAndValues)
+}
+
+func (l LoggerFuncs) Warn(msg string, keysAndValues ...any) {
+	callLogFunc(l.WarnFunc, msg, keysAndValues)
+}
+
+func (l LoggerFuncs) Error(msg string, keysAndValues ...any) {
+	callLogFunc(l.ErrorFunc, msg, keysAndValues)
+}
+
+func callLogFunc(f func(msg string, keysAndValues ...any), msg string, keysAndValues []any) {
+	if f != nil {
+		f(msg, keysAndValues...)
+	}
+}
+
+// KeyValues turns alternating keys and values into a map, e.g. for logrus:
+//
+//	unixcycle.LoggerFuncs{
+//		InfoFunc: func(msg string, keysAndValues ...any) { logrusLogger.WithFields(unixcycle.KeyValues(keysAndValues)).Info(msg) },
+//		...
+//	}
+func KeyValues(keysAndValues []any) map[string]any {
+	fields := make(map[string]any, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	return fields
+}
+
+// LoggerHandler adapts logger into a slog.Handler, to be used with WithLogger:
+//
+//	unixcycle.WithLogger(slog.New(unixcycle.LoggerHandler(logger)))
+//
+// Attributes are passed as alternating keys and values, with the keys of groups joined by dots.
+// Level filtering is left to logger
+func LoggerHandler(logger Logger) slog.Handler {
+	return &loggerHandler{logger: logger}
+}
+
+type loggerHandler struct {
+	logger        Logger
+	keysAndValues []any
+	group         string // Prefix of the keys, e.g. "request."
+}
+
+func (h *loggerHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *loggerHandler) Handle(_ context.Context, record slog.Record) error {
+	keysAndValues := slices.Clone(h.keysAndValues)
+	record.Attrs(func(attr slog.Attr) bool {
+		keysAndValues = appendKeyValues(keysAndValues, h.group, attr)
+		return true
+	})
+
+	switch {
+	case record.Level < slog.LevelInfo:
+		h.logger.Debug(record.Message, keysAndValues...)
+	case record.Level < slog.LevelWarn:
+		h.logger.Info(record.Message, keysAndValues...)
+	case record.Level < slog.LevelError:
+		h.logger.Warn(record.Message, keysAndValues...)
+	default:
+		h.logger.Error(record.Message, keysAndValues...)
+	}
+	return nil
+}
+
+func (h *loggerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	keysAndValues := slices.Clone(h.keysAndValues)
+	for _, attr := range attrs {
+		keysAndValues = appendKeyValues(keysAndValues, h.group, attr)
+	}
+	return &loggerHandler{logger: h.logger, keysAndValues: keysAndValues, group: h.group}
+}
+
+func (h *loggerHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &loggerHandler{logger: h.logger, keysAndValues: h.keysAndValues, group: h.group + name + "."}
+}
+
+// appendKeyValues flattens attr into keys and values, joining the keys of groups by dots
+func appendKeyValues(keysAndValues []any, prefix string, attr slog.Attr) []any {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return keysAndValues
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, nested := range attr.Value.Group() {
+			keysAndValues = appendKeyValues(keysAndValues, prefix, nested)
+		}
+		return keysAndValues
+	}
+	return append(keysAndValues, prefix+attr.Key, attr.Value.Any())
+}
@@ -0,0 +1,69 @@
+package unixcycle_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+type logCall struct {
+	level         string
+	msg           string
+	keysAndValues map[string]any
+}
+
+func TestLoggerHandler(t *testing.T) {
+	t.Run("should pass records to the logger method of their level with flattened attributes", func(t *testing.T) {
+		var (
+			calls  []logCall
+			record = func(level string) func(msg string, keysAndValues ...any) {
+				return func(msg string, keysAndValues ...any) {
+					calls = append(calls, logCall{level: level, msg: msg, keysAndValues: unixcycle.KeyValues(keysAndValues)})
+				}
+			}
+			sut = slog.New(unixcycle.LoggerHandler(unixcycle.LoggerFuncs{
+				DebugFunc: record("debug"),
+				InfoFunc:  record("info"),
+				ErrorFunc: record("error"),
+			}))
+		)
+
+		sut.With("service", "orders").WithGroup("component").Info("started", "name", "db", slog.Group("timing", "ms", 3))
+		sut.Error("failed", "code", "UC-START-FAILED")
+		sut.Warn("dropped, as there is no warn function")
+
+		assert.Equal(t, []logCall{
+			{level: "info", msg: "started", keysAndValues: map[string]any{"service": "orders", "component.name": "db", "component.timing.ms": int64(3)}},
+			{level: "error", msg: "failed", keysAndValues: map[string]any{"code": "UC-START-FAILED"}},
+		}, calls)
+	})
+
+	t.Run("should get the manager's logs into the logger", func(t *testing.T) {
+		var (
+			msgs []string
+			sut  = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithLogger(slog.New(unixcycle.LoggerHandler(unixcycle.LoggerFuncs{
+					InfoFunc: func(msg string, keysAndValues ...any) {
+						if unixcycle.KeyValues(keysAndValues)["component_name"] == "db" {
+							msgs = append(msgs, msg)
+						}
+					},
+				}))),
+			).Add("db", unixcycle.Closer(func() error { return nil }))
+		)
+
+		sut.Run()
+
+		assert.Equal(t, []string{`[UnixCycle] Starting component "db"`, `[UnixCycle] Closing component "db"`}, msgs)
+	})
+}
+
+func TestSetLogLevel(t *testing.T) {
+	t.Run("should only change the level of the default logger", func(t *testing.T) {
+		assert.NoError(t, unixcycle.NewManager().SetLogLevel(slog.LevelDebug))
+		assert.Error(t, unixcycle.NewManager(unixcycle.WithLogger(slog.Default())).SetLogLevel(slog.LevelDebug))
+	})
+}
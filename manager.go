@@ -1,35 +1,106 @@
 package unixcycle
 
 import (
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"runtime/debug"
 	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
 
-var errTimeout = fmt.Errorf("function did not complete within the given timeout")
-
 func defaultOptions() *managerOptions {
 	return &managerOptions{
-		logger:       slog.New(slog.NewTextHandler(os.Stdout, nil)),
-		setupTimeout: 5 * time.Second,
-		closeTimeout: 5 * time.Second,
-		lifetime:     InterruptSignal,
+		setupTimeout:      5 * time.Second,
+		closeTimeout:      5 * time.Second,
+		checkpointTimeout: 5 * time.Second,
+		drainTimeout:      5 * time.Second,
+		lifetime:          InterruptSignal,
+		logPrefix:         "[UnixCycle] ",
+		logKeys:           LogKeys{Component: componentNameKey, Code: "code"},
+		namesInMessages:   true,
+	}
+}
+
+// defaultLogger writes text, or JSON, to os.Stdout from level on
+func defaultLogger(level slog.Leveler, json bool) *slog.Logger {
+	options := &slog.HandlerOptions{Level: level}
+	if json {
+		return slog.New(slog.NewJSONHandler(os.Stdout, options))
 	}
+	return slog.New(slog.NewTextHandler(os.Stdout, options))
 }
 
 type Manager struct {
 	components []namedComponent
 
-	logger       *slog.Logger
-	setupTimeout time.Duration
-	closeTimeout time.Duration
-	lifetime     TerminationSignal
+	logger            *slog.Logger
+	logLevel          *slog.LevelVar // Level of the default logger, nil with WithLogger
+	logPrefix         string
+	logKeys           LogKeys
+	namesInMessages   bool
+	setupTimeout      time.Duration
+	closeTimeout      time.Duration
+	checkpointTimeout time.Duration
+	drainTimeout      time.Duration
+	lifetime          TerminationSignal
+	stateFile         string
+	afterShutdown     []func(Result)
+	exitCodeMapper    func(Result) int
+	onStarted         []func(time.Duration)
+	onSetupComplete   []func()
+	onShutdownStart   []func()
+	eventHandlers     []func(Event)
+	resourceInterval  time.Duration
+	statusOnSIGINFO   bool
+	closeGap          time.Duration
+	concurrentClose   bool
+	tracer            Tracer
+	panicHandler      func(component string, recovered any, stack []byte)
+	systemdNotify     bool
+	reloadSignals     []os.Signal
+	forwardSignals    bool
+	shutdownBudget    time.Duration
+	shutdownDelay     time.Duration
+	startupGroups     []string
+	upgradeCommand    []string
+	leaderGate        LeaderGate
+	forceExitAfter    time.Duration
+	readinessProber   ProberFunc
+	shutdownDeadline  time.Time // Set when shutdown begins, if there is a shutdown budget
 
 	exitSignal chan int
+	started    chan struct{} // Closed once all components are started
+	startup    *startup      // Set by Start
+	timedOut   atomic.Int64  // Operations still running after timing out
+	upgrading  atomic.Bool   // Whether Upgrade is in progress or succeeded
+	runState   atomic.Int32  // One of managerIdle, managerStarted, managerWaiting and managerStopped
+
+	shutdownCtx   context.Context // Cancelled when shutdown begins
+	beginShutdown context.CancelFunc
+	runCtx        context.Context // Cancelled when shutdown begins or all components are restarted, guarded by mu
+	cancelRun     context.CancelFunc
+	runnersCtx    context.Context // Cancelled once the components are drained on shutdown, see stopRunners
+	cancelRunners context.CancelFunc
+	generation    int  // Incremented every time all components are restarted, guarded by mu
+	leader        bool // Whether the leader gate reported leadership, guarded by lifecycle
+	runners       sync.WaitGroup
+	lifecycle     sync.Mutex // Serializes restarts with the shutdown sequence
+	supervision   supervisionOptions
+	restarts      map[string]int // Guarded by mu
+	abortErr      error          // Guarded by mu
+	addErrs       []error        // Components rejected by Add
+	signalHooks   []signalHook
+
+	mu           sync.Mutex // Guards the component statuses and recent events
+	recentEvents []statusEvent
 }
 
 func NewManager(options ...managerOption) *Manager {
@@ -38,112 +109,654 @@ func NewManager(options ...managerOption) *Manager {
 		o(ops)
 	}
 
+	logger, logLevel := ops.logger, (*slog.LevelVar)(nil)
+	if logger == nil {
+		logLevel = new(slog.LevelVar)
+		logLevel.Set(ops.logLevel)
+		logger = defaultLogger(logLevel, ops.jsonLogging)
+	}
+
 	return &Manager{
-		logger:       ops.logger,
-		setupTimeout: ops.setupTimeout,
-		closeTimeout: ops.closeTimeout,
-		lifetime:     ops.lifetime,
-		exitSignal:   make(chan int, 1),
+		logger:            logger,
+		logLevel:          logLevel,
+		logPrefix:         ops.logPrefix,
+		logKeys:           ops.logKeys,
+		namesInMessages:   ops.namesInMessages,
+		setupTimeout:      ops.setupTimeout,
+		closeTimeout:      ops.closeTimeout,
+		checkpointTimeout: ops.checkpointTimeout,
+		drainTimeout:      ops.drainTimeout,
+		lifetime:          ops.lifetime,
+		stateFile:         ops.stateFile,
+		afterShutdown:     ops.afterShutdown,
+		exitCodeMapper:    ops.exitCodeMapper,
+		onStarted:         ops.onStarted,
+		onSetupComplete:   ops.onSetupComplete,
+		onShutdownStart:   ops.onShutdownStart,
+		eventHandlers:     ops.eventHandlers,
+		resourceInterval:  ops.resourceInterval,
+		statusOnSIGINFO:   ops.statusOnSIGINFO,
+		closeGap:          ops.closeGap,
+		concurrentClose:   ops.concurrentClose,
+		tracer:            ops.tracer,
+		panicHandler:      ops.panicHandler,
+		systemdNotify:     ops.systemdNotify,
+		reloadSignals:     ops.reloadSignals,
+		forwardSignals:    ops.forwardSignals,
+		shutdownBudget:    ops.shutdownBudget,
+		shutdownDelay:     ops.shutdownDelay,
+		startupGroups:     ops.startupGroups,
+		upgradeCommand:    ops.upgradeCommand,
+		leaderGate:        ops.leaderGate,
+		forceExitAfter:    ops.forceExitAfter,
+		readinessProber:   ops.readinessProber,
+		supervision:       ops.supervision,
+		restarts:          map[string]int{},
+		exitSignal:        make(chan int, 1),
+		started:           make(chan struct{}),
 	}
 }
 
-func (m *Manager) Add(name string, components Component) *Manager {
-	m.components = append(m.components, namedComponent{name: name, Component: components})
+// Add registers a component under a unique name.
+// Empty or duplicate names and nil components are logged and not registered, and make Run fail before setup.
+// Components added once the manager started are logged and not registered either, see ErrAddAfterStart
+func (m *Manager) Add(name string, components Component, options ...componentOption) *Manager {
+	if err := m.checkAdd(name, components); err != nil {
+		m.logError(CodeComponentInvalid, fmt.Sprintf("Invalid component: %v", err), slog.String("component_name", name))
+		m.addErrs = append(m.addErrs, err)
+		return m
+	}
+
+	ops := componentOptions{}
+	for _, o := range options {
+		o(&ops)
+	}
+
+	m.components = append(m.components, namedComponent{
+		name:      name,
+		Component: components,
+		options:   ops,
+		status:    &componentStatus{},
+	})
+	m.components[len(m.components)-1].status.enter(StateRegistered, time.Now())
 
 	return m
 }
 
+// MustAdd is like Add, but panics on an empty or duplicate name, a nil component or when the manager already started
+func (m *Manager) MustAdd(name string, component Component, options ...componentOption) *Manager {
+	if err := m.checkAdd(name, component); err != nil {
+		panic(err)
+	}
+	return m.Add(name, component, options...)
+}
+
+// Defer registers a cleanup function that is run during the close phase.
+// Deferred functions are closed in LIFO order together with the components, with the same logging and timeouts
+func (m *Manager) Defer(name string, cleanup func() error) *Manager {
+	return m.Add(name, Closer(cleanup))
+}
+
+// Reset returns a stopped manager to the state NewManager left it in, keeping its options but not its components
+// and signal hooks, so tests can reuse a configured manager instead of building a new one for every case.
+// A manager that is running can't be reset
+func (m *Manager) Reset() error {
+	if state := m.runState.Load(); state == managerStarted || state == managerWaiting {
+		return ErrAlreadyRunning
+	}
+
+	m.lifecycle.Lock()
+	defer m.lifecycle.Unlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.components, m.addErrs, m.signalHooks, m.recentEvents = nil, nil, nil, nil
+	m.exitSignal, m.started, m.startup = make(chan int, 1), make(chan struct{}), nil
+	m.shutdownCtx, m.beginShutdown, m.runCtx, m.cancelRun = nil, nil, nil, nil
+	m.runnersCtx, m.cancelRunners = nil, nil
+	m.shutdownDeadline, m.generation, m.leader = time.Time{}, 0, false
+	m.restarts, m.abortErr = map[string]int{}, nil
+	m.timedOut.Store(0)
+	m.upgrading.Store(false)
+	m.runState.Store(managerIdle)
+	return nil
+}
+
 func (m *Manager) Run() int {
-	err := m.setupComponents()
-	if errors.Is(err, errTimeout) {
-		return int(syscall.SIGALRM)
+	return m.runWithResult(context.Background()).ExitCode
+}
+
+// RunContext runs the manager like Run, but also shuts down gracefully when ctx is done,
+// exactly as if the lifetime had returned 0. Useful when embedding the manager in a larger program
+func (m *Manager) RunContext(ctx context.Context) int {
+	return m.runWithResult(ctx).ExitCode
+}
+
+// RunWithResult runs the manager exactly like Run, but returns the structured Result
+// telling which phase and components failed and how long each part of the lifecycle took
+func (m *Manager) RunWithResult() Result {
+	return m.runWithResult(context.Background())
+}
+
+func (m *Manager) runWithResult(ctx context.Context) Result {
+	return m.WaitContext(ctx)
+}
+
+// Start sets up and starts the components, and returns without waiting for the exit signal.
+// Use it to run code between the startup and Wait, e.g. when embedding the manager in a larger program.
+// With startup groups, the later groups are still being started when Start returns, see Started.
+// Returns the error of a failed startup, after which Wait returns right away with the failed Result
+func (m *Manager) Start() error {
+	if !m.runState.CompareAndSwap(managerIdle, managerStarted) {
+		return m.misused()
+	}
+
+	m.startup = m.start()
+	if m.startup.failed != nil {
+		return m.startup.failed.Err
+	}
+	return nil
+}
+
+// Wait waits for the exit signal and shuts the components down, like Run. Starts the components first if Start was not called
+func (m *Manager) Wait() Result {
+	return m.WaitContext(context.Background())
+}
+
+// WaitContext waits like Wait, but also shuts down gracefully when ctx is done, exactly as if the lifetime had returned 0
+func (m *Manager) WaitContext(ctx context.Context) Result {
+	switch {
+	case m.runState.CompareAndSwap(managerIdle, managerWaiting):
+		m.startup = m.start()
+	case m.runState.CompareAndSwap(managerStarted, managerWaiting):
+	default:
+		result := newResult(0, m.misused())
+		result.ExitCode = result.Signal
+		return result
+	}
+	defer m.runState.Store(managerStopped)
+
+	result := m.wait(ctx, m.startup)
+	result.ComponentErrors = m.componentErrors()
+	m.logTimedOut()
+	m.emit(EventShutdownCompleted, "", "", result.Err)
+	result.ExitCode = result.Signal
+	if m.exitCodeMapper != nil {
+		result.ExitCode = m.exitCodeMapper(result)
+	}
+
+	for _, hook := range m.afterShutdown {
+		hook(result)
+	}
+
+	return result
+}
+
+// Run states of a manager, which runs only once
+const (
+	managerIdle    int32 = iota
+	managerStarted       // Start returned, Wait was not called yet
+	managerWaiting       // Waiting for the exit signal or shutting down
+	managerStopped
+)
+
+// misused logs and returns the error for starting or waiting on the manager in its current run state
+func (m *Manager) misused() error {
+	err := ErrAlreadyRunning
+	if m.runState.Load() == managerStopped {
+		err = ErrAlreadyStopped
+	}
+	m.logError(CodeManagerMisused, fmt.Sprintf("Manager misused: %v", err))
+	return err
+}
+
+// startup is the state of a run between Start and Wait
+type startup struct {
+	began         time.Time
+	setupDuration time.Duration
+	runBegan      time.Time
+	failed        *Result  // Set if the startup failed before any component was started
+	stop          []func() // Stops what was started next to the components, in reverse order
+}
+
+func (m *Manager) start() *startup {
+	var (
+		s      = &startup{began: time.Now()}
+		failed = func(result Result) *startup {
+			s.failed = &result
+			return s
+		}
+	)
+	if len(m.addErrs) > 0 {
+		return failed(failedResult(PhaseAdd, errors.Join(m.addErrs...)))
 	}
+
+	err := m.orderComponents()
 	if err != nil {
-		return int(syscall.SIGABRT)
+		return failed(failedResult(PhaseDependencies, err))
 	}
 
-	m.startComponents()
+	err = m.validateGroups()
+	if err != nil {
+		return failed(failedResult(PhaseDependencies, err))
+	}
 
-	signal := m.waitForSignal() // Wait for the exit signal
+	err = m.validateLeaderOnly()
+	if err != nil {
+		return failed(failedResult(PhaseDependencies, err))
+	}
 
-	err = m.closeComponents()
-	if errors.Is(err, errTimeout) {
-		return int(syscall.SIGALRM)
+	err = m.restoreComponentStates()
+	if err != nil {
+		return failed(failedResult(PhaseRestoreState, err))
 	}
+
+	setupBegan := time.Now()
+	setUp, err := m.setupComponents()
 	if err != nil {
-		return int(syscall.SIGABRT)
+		result := failedResult(PhaseSetup, errors.Join(err, m.rollbackComponents(setUp)))
+		result.SetupDuration = time.Since(setupBegan)
+		return failed(result)
+	}
+	s.setupDuration = time.Since(setupBegan)
+	m.logDebug(CodeTiming, fmt.Sprintf("Setup of all components took %s", s.setupDuration), slog.String("operation", "Setup"), slog.Duration("duration", s.setupDuration))
+	for _, hook := range m.onSetupComplete {
+		hook()
 	}
 
-	return signal
-}
+	m.shutdownCtx, m.beginShutdown = context.WithCancel(context.Background())
+	m.runCtx, m.cancelRun = context.WithCancel(m.shutdownCtx)
+	m.runnersCtx, m.cancelRunners = context.WithCancel(context.Background())
+	s.stop = append(s.stop, m.beginShutdown, m.cancelRunners, m.reloadOnSignals(), m.runSignalHooks(), m.campaign())
 
-func (m *Manager) setupComponents() error {
-	for _, s := range m.components {
-		setupable, ok := s.Component.(setupable)
-		if ok {
-			m.logInfo(fmt.Sprintf("Setting up component %q", s.name), slog.String("component_name", s.name))
-			err := funcOrTimeout(setupable.Setup, m.setupTimeout)
-			if errors.Is(err, errTimeout) {
-				m.logError(fmt.Sprintf("Setup timed out for component %q", s.name), slog.String("component_name", s.name))
-				return err
-			}
-			if err != nil {
-				m.logError(fmt.Sprintf("Failure during setup for component %q: %v", s.name, err), slog.String("component_name", s.name))
-				return err
+	s.runBegan = time.Now()
+	if len(m.startupGroups) == 0 {
+		if m.startComponents() && m.probeReadiness() {
+			m.componentsStarted(s.began)
+		}
+	} else {
+		// Wait for the groups to become ready in the background, so a signal can interrupt the startup.
+		// Shutdown waits for the startup to settle, which stops early once shutdown begins
+		m.lifecycle.Lock()
+		go func() {
+			defer m.lifecycle.Unlock()
+			if m.startComponents() && m.probeReadiness() {
+				m.componentsStarted(s.began)
 			}
+		}()
+	}
+
+	if m.statusOnSIGINFO {
+		s.stop = append(s.stop, m.notifyStatusOnInfoSignal(os.Stderr))
+	}
+
+	if m.resourceInterval > 0 {
+		ctx, stopReporting := context.WithCancel(context.Background())
+		s.stop = append(s.stop, stopReporting)
+		go m.reportResourceUsage(ctx, m.resourceInterval)
+	}
+
+	return s
+}
+
+func (m *Manager) wait(ctx context.Context, s *startup) Result {
+	if s.failed != nil {
+		return *s.failed
+	}
+	defer func() {
+		for _, stop := range slices.Backward(s.stop) {
+			stop()
 		}
+	}()
+
+	signal := m.waitForSignal(ctx) // Wait for the exit signal
+	defer m.armForceExit()()
+	runDuration := time.Since(s.runBegan)
+
+	shutdownBegan := time.Now()
+	if m.shutdownDelay > 0 && m.abortError() == nil {
+		m.logInfo(CodeShutdownDelay, fmt.Sprintf("Delaying shutdown by %s", m.shutdownDelay))
+		time.Sleep(m.shutdownDelay) // Keep serving while load balancers stop sending traffic
 	}
+
+	m.beginShutdown()
+	m.shutdownBegun()
+	m.forwardSignal(signal)
+	m.lifecycle.Lock() // Let a restart in progress settle before closing anything
+	defer m.lifecycle.Unlock()
+
+	if m.shutdownBudget > 0 {
+		m.shutdownDeadline = shutdownBegan.Add(m.shutdownBudget)
+	}
+
+	// Components are still closed even if they could not checkpoint, drain or save their state
+	var (
+		abortErr      = m.abortError()
+		checkpointErr = m.checkpointComponents()
+		drainErr      = m.drainComponents()
+	)
+	m.stopRunners()
+	var (
+		stateErr = m.saveComponentStates()
+		closeErr = m.closeComponents(m.components)
+	)
+
+	result := newResult(signal, errors.Join(abortErr, drainErr, checkpointErr, stateErr, closeErr))
+	for _, failure := range []struct {
+		phase Phase
+		err   error
+	}{{PhaseStart, abortErr}, {PhaseCheckpoint, checkpointErr}, {PhaseDrain, drainErr}, {PhaseSaveState, stateErr}, {PhaseClose, closeErr}} {
+		if failure.err != nil {
+			result.FailedPhase = failure.phase
+			break
+		}
+	}
+	result.SetupDuration = s.setupDuration
+	result.RunDuration = runDuration
+	result.ShutdownDuration = time.Since(shutdownBegan)
+	m.logDebug(CodeTiming, fmt.Sprintf("Shutdown took %s", result.ShutdownDuration), slog.String("operation", "Shutdown"), slog.Duration("duration", result.ShutdownDuration))
+
+	return result
+}
+
+// setupComponents returns the components that were successfully set up before any failure
+func (m *Manager) setupComponents() ([]namedComponent, error) {
+	for i, s := range m.components {
+		if m.awaitsLeadership(s) {
+			m.setState(s, StateStandby, "", nil)
+			continue
+		}
+		if err := m.setupComponent(s); err != nil {
+			return m.components[:i], err
+		}
+	}
+	return m.components, nil
+}
+
+func (m *Manager) setupComponent(s namedComponent) error {
+	if loggerAware, ok := s.Component.(loggerAware); ok {
+		loggerAware.SetLogger(m.logger.With(slog.String(m.logKeys.Component, s.name)))
+	}
+
+	setupable, ok := s.Component.(setupable)
+	if !ok {
+		return nil
+	}
+
+	m.logInfo(CodeSetupBegin, fmt.Sprintf("Setting up component %q", s.name), slog.String("component_name", s.name))
+	m.setState(s, StateSettingUp, CodeSetupBegin, nil)
+	began := time.Now()
+	err := m.traced("setup", s, func() error {
+		return funcOrTimeout(setupable.Setup, cmp.Or(s.options.setupTimeout, m.setupTimeout))
+	})
+	if errors.Is(err, errTimeout) {
+		m.trackTimedOut(s, "Setup", err)
+		m.logError(CodeSetupTimeout, fmt.Sprintf("Setup timed out for component %q", s.name), slog.String("component_name", s.name))
+		m.setState(s, StateFailed, CodeSetupTimeout, err)
+		return newComponentError(s, ErrSetupTimeout, err)
+	}
+	if err != nil {
+		m.logError(CodeSetupFailed, fmt.Sprintf("Failure during setup for component %q: %v", s.name, err), slog.String("component_name", s.name))
+		m.setState(s, StateFailed, CodeSetupFailed, err)
+		return newComponentError(s, ErrSetupFailed, err)
+	}
+	m.recordTiming(s, "Setup", began)
+	m.setState(s, StateSetUp, "", nil)
 	return nil
 }
 
-func (m *Manager) startComponents() {
-	for _, s := range m.components {
-		startable, ok := s.Component.(startable)
-		if ok {
-			m.logInfo(fmt.Sprintf("Starting component %q", s.name), slog.String("component_name", s.name))
-			go func() {
-				defer func() {
-					if r := recover(); r != nil {
-						m.logError(fmt.Sprintf("Panic during start for component %q: %v", s.name, r), slog.String("component_name", s.name))
-						m.exitSignal <- int(syscall.SIGABRT)
-					}
-				}()
-				err := startable.Start() // Blocking for go routine
-				if err != nil {
-					m.logError(fmt.Sprintf("Failure during start for component %q: %v", s.name, err), slog.String("component_name", s.name))
-					m.exitSignal <- int(syscall.SIGABRT)
-				}
-			}()
+// rollbackComponents closes the components that were set up before setup aborted, so they don't leak connections and goroutines
+func (m *Manager) rollbackComponents(setUp []namedComponent) error {
+	if len(setUp) == 0 {
+		return nil
+	}
+
+	m.logInfo(CodeRollbackBegin, fmt.Sprintf("Rolling back %d components after setup failure", len(setUp)))
+	return m.closeComponents(setUp)
+}
+
+// launch runs the long running part of a component in its own goroutine, as part of the current generation of components
+func (m *Manager) launch(s namedComponent) {
+	runCtx, generation := m.currentRun()
+	// Runnable components keep running while being drained on shutdown, until stopRunners cancels them.
+	// Restarting all components cancels them right away, and cancel lets Restart stop a single one
+	ctx, cancel := context.WithCancel(m.runnersCtx)
+	stopRestart := context.AfterFunc(runCtx, func() {
+		if m.shutdownCtx.Err() == nil {
+			cancel()
+		}
+	})
+	start, isRunnable, ok := startFunc(s.Component, ctx)
+	if !ok {
+		stopRestart()
+		cancel()
+		return
+	}
+
+	m.logInfo(CodeStartBegin, fmt.Sprintf("Starting component %q", s.name), slog.String("component_name", s.name))
+	if m.tracer != nil {
+		defer m.tracer("start", s.name)(nil) // Only covers launching, Start runs for the lifetime of the component
+	}
+	m.markLaunched(s, cancel)
+	m.setState(s, StateRunning, CodeStartBegin, nil)
+	if isRunnable {
+		m.runners.Add(1)
+	}
+	go withComponentLabel(s.name, "start", func() {
+		if isRunnable {
+			defer m.runners.Done()
+		}
+		defer cancel()
+		defer stopRestart()
+		err, panicked := callRecovering(start) // Blocking for go routine
+		if isRunnable && ctx.Err() != nil && errors.Is(err, context.Canceled) {
+			err = nil // Cancelled by us on shutdown or restart
+		}
+		if m.isRestarting(s) {
+			m.markStartExited(s, err)
+			return // Stopped by Restart, which takes care of the state
+		}
+		if err == nil {
+			err = m.componentExited(s, generation)
+		}
+		m.markStartExited(s, err)
+		if err == nil {
+			m.setState(s, StateStopped, "", nil)
+			return
+		}
+
+		code := CodeStartFailed
+		if panicked {
+			m.handlePanic(s, err)
+			code = CodeStartPanic
+			m.logError(code, fmt.Sprintf("Panic during start for component %q: %v", s.name, err), slog.String("component_name", s.name))
+		} else {
+			m.logError(code, fmt.Sprintf("Failure during start for component %q: %v", s.name, err), slog.String("component_name", s.name))
+		}
+		m.setState(s, StateFailed, code, err)
+		if panicked {
+			m.componentPanicked(s, generation, err)
+			return
+		}
+		m.componentFailed(s, generation, err)
+	})
+}
+
+// callRecovering calls f, converting a panic into a *panicError
+func callRecovering(f func() error) (err error, panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, panicked = &panicError{value: r, stack: debug.Stack()}, true
 		}
+	}()
+
+	return f(), false
+}
+
+// startFunc returns the long running function of the component, preferring Run(ctx) over Start
+func startFunc(c Component, runCtx context.Context) (start func() error, isRunnable bool, ok bool) {
+	if runnable, ok := c.(runnable); ok {
+		return func() error { return runnable.Run(runCtx) }, true, true
+	}
+	if startable, ok := c.(startable); ok {
+		return startable.Start, false, true
+	}
+	return nil, false, false
+}
+
+// closeFunc returns the function closing the component, preferring CloseContext(ctx) over Close. Nil if it can't be closed
+func closeFunc(c Component) func(ctx context.Context) error {
+	if closable, ok := c.(contextClosable); ok {
+		return closable.CloseContext
+	}
+	if closable, ok := c.(closable); ok {
+		return func(context.Context) error { return closable.Close() }
 	}
+	return nil
+}
+
+// currentRun returns the context and generation of the currently running components.
+// A new generation begins every time all components are restarted
+func (m *Manager) currentRun() (context.Context, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.runCtx, m.generation
 }
 
-func (m *Manager) waitForSignal() int {
+// stopRunners cancels the context of all runnable components and waits for them to return, bounded by the close timeout.
+// It is called once the components are drained, so runnable components can still finish their work while draining
+func (m *Manager) stopRunners() {
+	m.beginShutdown()
+	m.cancelRunners()
+
+	err := funcOrTimeout(func() error { m.runners.Wait(); return nil }, m.withinShutdownBudget(m.closeTimeout))
+	if errors.Is(err, errTimeout) {
+		m.logError(CodeRunCancelTimeout, "Runnable components did not return within the close timeout after being cancelled")
+	}
+}
+
+// abort makes the manager shut down with SIGABRT, reporting err as the cause in the Result.
+// Only the first cause is kept
+func (m *Manager) abort(err error) {
+	m.mu.Lock()
+	if m.abortErr == nil {
+		m.abortErr = err
+	}
+	m.mu.Unlock()
+
+	m.signal(int(syscall.SIGABRT))
+}
+
+func (m *Manager) abortError() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.abortErr
+}
+
+// signal makes the manager shut down with sig, unless a signal was already sent
+func (m *Manager) signal(sig int) {
+	select {
+	case m.exitSignal <- sig:
+	default:
+		// Signal already sent, don't block
+	}
+}
+
+func (m *Manager) waitForSignal(ctx context.Context) int {
 	go func() {
-		select {
-		case m.exitSignal <- m.lifetime():
-		default:
-			// Signal already sent, don't block
-		}
+		m.signal(m.lifetime())
 	}()
 
-	signal := <-m.exitSignal
-	m.logInfo(fmt.Sprintf("Received signal: %d", signal), slog.Int("signal", signal))
-	return signal
+	select {
+	case signal := <-m.exitSignal:
+		m.logInfo(CodeSignalReceived, fmt.Sprintf("Received signal: %d", signal), slog.Int("signal", signal))
+		return signal
+	case <-ctx.Done():
+		m.logInfo(CodeContextDone, fmt.Sprintf("Context done: %v", context.Cause(ctx)))
+		return 0
+	}
+}
+
+// drainComponents lets every component stop taking new work and finish the work in flight, in reverse order.
+// All components are drained even if one fails
+func (m *Manager) drainComponents() error {
+	var errs []error
+	for _, s := range slices.Backward(m.components) {
+		if err := m.drainComponent(s); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *Manager) drainComponent(s namedComponent) error {
+	drainable, ok := s.Component.(drainable)
+	if !ok || m.state(s) == StateStandby {
+		return nil
+	}
+
+	m.logInfo(CodeDrainBegin, fmt.Sprintf("Draining component %q", s.name), slog.String("component_name", s.name))
+	m.setState(s, StateDraining, CodeDrainBegin, nil)
+	began := time.Now()
+	err := contextFuncOrTimeout(drainable.Drain, m.withinShutdownBudget(m.drainTimeout))
+	if errors.Is(err, errTimeout) {
+		m.trackTimedOut(s, "Drain", err)
+		m.logError(CodeDrainTimeout, fmt.Sprintf("Drain timed out for component %q", s.name), slog.String("component_name", s.name))
+		return err
+	}
+	if err != nil {
+		m.logError(CodeDrainFailed, fmt.Sprintf("Failure during drain for component %q: %v", s.name, err), slog.String("component_name", s.name))
+		return err
+	}
+	m.recordTiming(s, "Drain", began)
+	return nil
 }
 
-func (m *Manager) closeComponents() error {
+// checkpointComponents gives every component a chance to flush buffers and commit offsets
+// while the rest of the system is still up. All components are checkpointed even if one fails
+func (m *Manager) checkpointComponents() error {
+	var errs []error
 	for _, s := range slices.Backward(m.components) {
-		closable, ok := s.Component.(closable)
-		if ok {
-			m.logInfo(fmt.Sprintf("Closing component %q", s.name), slog.String("component_name", s.name))
-			err := funcOrTimeout(closable.Close, m.closeTimeout)
+		checkpointable, ok := s.Component.(checkpointable)
+		if ok && m.state(s) != StateStandby {
+			m.logInfo(CodeCheckpointBegin, fmt.Sprintf("Checkpointing component %q", s.name), slog.String("component_name", s.name))
+			began := time.Now()
+			err := contextFuncOrTimeout(checkpointable.Checkpoint, m.withinShutdownBudget(m.checkpointTimeout))
 			if errors.Is(err, errTimeout) {
-				m.logError(fmt.Sprintf("Close timed out for component %q", s.name), slog.String("component_name", s.name))
-				return err
+				m.trackTimedOut(s, "Checkpoint", err)
+				m.logError(CodeCheckpointTimeout, fmt.Sprintf("Checkpoint timed out for component %q", s.name), slog.String("component_name", s.name))
+				errs = append(errs, err)
+				continue
 			}
 			if err != nil {
-				m.logError(fmt.Sprintf("Failure during close for component %q: %v", s.name, err), slog.String("component_name", s.name))
+				m.logError(CodeCheckpointFailed, fmt.Sprintf("Failure during checkpoint for component %q: %v", s.name, err), slog.String("component_name", s.name))
+				errs = append(errs, err)
+				continue
+			}
+			m.recordTiming(s, "Checkpoint", began)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *Manager) closeComponents(components []namedComponent) error {
+	if m.concurrentClose {
+		return m.closeComponentsConcurrently(components)
+	}
+
+	closedAny := false
+	for _, s := range closingOrder(components) {
+		if m.needsClose(s) {
+			if closedAny && m.closeGap > 0 {
+				time.Sleep(m.closeGap) // Let downstream components observe the previous one disappearing
+			}
+			closedAny = true
+
+			if err := m.closeComponent(s); err != nil {
 				return err
 			}
 		}
@@ -152,25 +765,167 @@ func (m *Manager) closeComponents() error {
 	return nil
 }
 
-func (m *Manager) logInfo(msg string, attrs ...any) {
-	m.logger.Info("[UnixCycle] "+msg, attrs...)
-}
+// closeComponentsConcurrently closes every component as soon as all components depending on it are closed,
+// so components without a dependency between them are closed concurrently.
+// When a component fails to close, the components it depends on are left open like in a sequential close
+func (m *Manager) closeComponentsConcurrently(components []namedComponent) error {
+	var (
+		done       = make(map[string]chan struct{}, len(components))
+		dependents = make(map[string][]string, len(components))
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		failed     = map[string]bool{}
+		errs       []error
+	)
+	for _, s := range components {
+		done[s.name] = make(chan struct{})
+		for _, dep := range s.options.dependsOn {
+			dependents[dep] = append(dependents[dep], s.name)
+		}
+	}
+
+	// The close order only adds waits that agree with the sequential closing order, which already resolves
+	// close orders conflicting with dependencies, so components never end up waiting for each other
+	position := make(map[string]int, len(components))
+	for i, s := range closingOrder(components) {
+		position[s.name] = i
+	}
+	for _, s := range components {
+		for _, other := range components {
+			if closeRank(other) < closeRank(s) && position[other.name] < position[s.name] {
+				dependents[s.name] = append(dependents[s.name], other.name)
+			}
+		}
+	}
 
-func (m *Manager) logError(msg string, attrs ...any) {
-	m.logger.Error("[UnixCycle] "+msg, attrs...)
+	for _, s := range components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[s.name])
+
+			dependentFailed := false
+			for _, dependent := range dependents[s.name] {
+				<-done[dependent]
+				mu.Lock()
+				dependentFailed = dependentFailed || failed[dependent]
+				mu.Unlock()
+			}
+			if dependentFailed || !m.needsClose(s) {
+				mu.Lock()
+				failed[s.name] = dependentFailed
+				mu.Unlock()
+				return
+			}
+
+			if len(dependents[s.name]) > 0 && m.closeGap > 0 {
+				time.Sleep(m.closeGap) // Let the dependents' downstream components observe them disappearing
+			}
+			if err := m.closeComponent(s); err != nil {
+				mu.Lock()
+				failed[s.name] = true
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
 }
 
-// NOTE: goroutine may leak on timeout, but acceptable since timeout usually always leaves to a library shutdown
-func funcOrTimeout(f func() error, timeout time.Duration) error {
-	errs := make(chan error, 1)
-	go func() {
-		errs <- f()
-	}()
+// needsClose reports whether s is closable and not closed yet.
+// Components are never closed twice, e.g. when shutting down during a restart, nor while waiting for leadership
+func (m *Manager) needsClose(s namedComponent) bool {
+	state := m.state(s)
+	return closeFunc(s.Component) != nil && state != StateClosed && state != StateStandby
+}
 
-	select {
-	case err := <-errs:
+func (m *Manager) closeComponent(s namedComponent) error {
+	m.logInfo(CodeCloseBegin, fmt.Sprintf("Closing component %q", s.name), slog.String("component_name", s.name))
+	m.setState(s, StateClosing, CodeCloseBegin, nil)
+	began := time.Now()
+	err := m.traced("close", s, func() (err error) {
+		withComponentLabel(s.name, "close", func() {
+			err = contextFuncOrTimeout(closeFunc(s.Component), m.withinShutdownBudget(cmp.Or(s.options.closeTimeout, m.closeTimeout)))
+		})
 		return err
-	case <-time.After(timeout):
-		return errTimeout
+	})
+	if errors.Is(err, errTimeout) {
+		m.trackTimedOut(s, "Close", err)
+		m.setState(s, StateFailed, m.logCloseTimeout(s), err)
+		return newComponentError(s, ErrCloseTimeout, err)
+	}
+	if err != nil {
+		m.logError(CodeCloseFailed, fmt.Sprintf("Failure during close for component %q: %v", s.name, err), slog.String("component_name", s.name))
+		m.setState(s, StateFailed, CodeCloseFailed, err)
+		return newComponentError(s, ErrCloseFailed, err)
+	}
+	m.recordTiming(s, "Close", began)
+	m.setState(s, StateClosed, "", nil)
+	return nil
+}
+
+// logCloseTimeout diagnoses a Close that timed out. When the Start goroutine already returned, Close is most likely
+// blocked on a channel that Start no longer reads, so both goroutines are named and the stuck Close stack is logged.
+// Returns the code it logged
+func (m *Manager) logCloseTimeout(s namedComponent) Code {
+	exited, startErr := m.startExited(s)
+	if !exited {
+		m.logError(CodeCloseTimeout, fmt.Sprintf("Close timed out for component %q", s.name), slog.String("component_name", s.name))
+		return CodeCloseTimeout
 	}
+
+	m.logError(
+		CodeCloseDeadlock,
+		fmt.Sprintf("Close timed out for component %q after its start goroutine already returned (error: %v). The close goroutine is likely blocked on a channel the start goroutine no longer serves", s.name, startErr),
+		slog.String("component_name", s.name),
+		slog.Any("start_error", startErr),
+		slog.String("close_stack", strings.Join(goroutineStacks(s.name, "close"), "\n\n")),
+	)
+	return CodeCloseDeadlock
+}
+
+func (m *Manager) logInfo(code Code, msg string, attrs ...any) {
+	m.recordEvent(slog.LevelInfo, code, msg)
+	m.log(slog.LevelInfo, code, msg, attrs)
+}
+
+// recordTiming keeps how long an operation of a component took for Components, and logs it at debug level
+func (m *Manager) recordTiming(s namedComponent, operation string, began time.Time) {
+	took := time.Since(began)
+	m.mu.Lock()
+	switch operation {
+	case "Setup":
+		s.status.timings.Setup = took
+	case "Drain":
+		s.status.timings.Drain = took
+	case "Checkpoint":
+		s.status.timings.Checkpoint = took
+	case "Close":
+		s.status.timings.Close = took
+	}
+	m.mu.Unlock()
+	m.logDebug(CodeTiming, fmt.Sprintf("%s of component %q took %s", operation, s.name, took),
+		slog.String("component_name", s.name), slog.String("operation", operation), slog.Duration("duration", took))
+}
+
+// logDebug logs details that are too chatty for the info level, like timings. They are not kept for the status page
+func (m *Manager) logDebug(code Code, msg string, attrs ...any) {
+	m.log(slog.LevelDebug, code, msg, attrs)
+}
+
+func (m *Manager) logError(code Code, msg string, attrs ...any) {
+	m.recordEvent(slog.LevelError, code, msg)
+	m.log(slog.LevelError, code, msg, attrs)
+}
+
+// withinShutdownBudget caps timeout to what is left of the shutdown budget, if shutdown began and a budget is set.
+// Once the budget is exhausted the timeout is zero, so every remaining step times out right away
+func (m *Manager) withinShutdownBudget(timeout time.Duration) time.Duration {
+	if m.shutdownDeadline.IsZero() {
+		return timeout
+	}
+
+	return max(min(timeout, time.Until(m.shutdownDeadline)), 0)
 }
@@ -1,12 +1,24 @@
 package unixcycle_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/theonewiththewrench/unixcycle"
 )
 
@@ -171,6 +183,1110 @@ func TestManager(t *testing.T) {
 		assert.Equal(t, closedCalled, true, "closable func should have been called")
 		assert.Equal(t, int(syscall.SIGABRT), got)
 	})
+
+	t.Run("should restore state saved by a previous run", func(t *testing.T) {
+		var (
+			stateFile = filepath.Join(t.TempDir(), "state.json")
+			newSut    = func(comp *statefulComponent) (*unixcycle.Manager, func(int)) {
+				shutdownChan := make(chan int, 1)
+				m := unixcycle.NewManager(
+					unixcycle.WithLifetime(manualSignal(shutdownChan)),
+					unixcycle.WithStateFile(stateFile),
+				)
+				return m.Add("stateful", comp), func(signal int) { shutdownChan <- signal }
+			}
+			first  = &statefulComponent{state: []byte("offset=42")}
+			second = &statefulComponent{}
+		)
+
+		sut, shutdown := newSut(first)
+		shutdown(0)
+		got := sut.Run()
+		assert.Equal(t, 0, got)
+
+		sut, shutdown = newSut(second)
+		shutdown(0)
+		got = sut.Run()
+		assert.Equal(t, 0, got)
+		assert.Equal(t, []byte("offset=42"), second.restored)
+	})
+
+	t.Run("should receive SIGABRT if state can not be saved", func(t *testing.T) {
+		var (
+			comp        = &statefulComponent{saveErr: assert.AnError}
+			closeCalled = false
+			sut         = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithStateFile(filepath.Join(t.TempDir(), "state.json")),
+			).
+				Add("stateful", comp).
+				Add("closable func", unixcycle.Closer(func() error { closeCalled = true; return nil }))
+		)
+
+		got := sut.Run()
+
+		assert.True(t, closeCalled, "components should still be closed")
+		assert.Equal(t, int(syscall.SIGABRT), got)
+	})
+
+	t.Run("should still save the state of the other components if one can not be saved", func(t *testing.T) {
+		var (
+			stateFile = filepath.Join(t.TempDir(), "state.json")
+			newSut    = func(failing, healthy *statefulComponent) *unixcycle.Manager {
+				return unixcycle.NewManager(
+					unixcycle.WithLifetime(func() int { return 0 }),
+					unixcycle.WithStateFile(stateFile),
+				).
+					Add("failing", failing).
+					Add("healthy", healthy)
+			}
+			restoredFailing = &statefulComponent{}
+			restoredHealthy = &statefulComponent{}
+		)
+
+		got := newSut(&statefulComponent{saveErr: assert.AnError}, &statefulComponent{state: []byte("offset=42")}).Run()
+		assert.Equal(t, int(syscall.SIGABRT), got)
+
+		got = newSut(restoredFailing, restoredHealthy).Run()
+		assert.Equal(t, 0, got)
+		assert.Equal(t, []byte("offset=42"), restoredHealthy.restored)
+		assert.Nil(t, restoredFailing.restored)
+	})
+
+	t.Run("should not time out a close returning once its context is done", func(t *testing.T) {
+		for range 20 {
+			sut := unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithCloseTimeout(20*time.Millisecond),
+			).Add("server", &contextClosingComponent{})
+
+			got := sut.RunWithResult()
+
+			require.NoError(t, got.Err)
+		}
+	})
+
+	t.Run("should checkpoint all components before closing any", func(t *testing.T) {
+		var (
+			m, shutdown = newManager()
+			calls       []string
+			first       = &checkpointComponent{name: "first", calls: &calls}
+			second      = &checkpointComponent{name: "second", calls: &calls, checkpointErr: assert.AnError}
+			sut         = m.Add("first", first).Add("second", second)
+		)
+
+		shutdown(0)
+		got := sut.Run()
+
+		assert.Equal(t, []string{"checkpoint second", "checkpoint first", "close second", "close first"}, calls)
+		assert.Equal(t, int(syscall.SIGABRT), got)
+	})
+
+	t.Run("should checkpoint all components before draining or closing any", func(t *testing.T) {
+		var (
+			m, shutdown = newManager()
+			calls       []string
+			first       = &drainComponent{checkpointComponent{name: "first", calls: &calls}}
+			second      = &drainComponent{checkpointComponent{name: "second", calls: &calls}}
+			sut         = m.Add("first", first).Add("second", second)
+		)
+
+		shutdown(0)
+		got := sut.Run()
+
+		assert.Equal(t, []string{"checkpoint second", "checkpoint first", "drain second", "drain first", "close second", "close first"}, calls)
+		assert.Equal(t, 0, got)
+	})
+
+	t.Run("should drain runnable components while they are still running", func(t *testing.T) {
+		var (
+			m, shutdown = newManager()
+			sut         = &drainRunnable{running: make(chan struct{})}
+		)
+		m.Add("consumer", sut)
+
+		shutdown(0)
+		got := m.Run()
+
+		assert.Equal(t, 0, got)
+		assert.NoError(t, sut.drainErr, "should not be cancelled before it is drained")
+		assert.True(t, sut.cancelled, "should be cancelled once drained")
+	})
+
+	t.Run("should run deferred cleanup in LIFO order", func(t *testing.T) {
+		var (
+			m, shutdown = newManager()
+			calls       []string
+			sut         = m.
+					Defer("first", func() error { calls = append(calls, "first"); return nil }).
+					Add("closable func", unixcycle.Closer(func() error { calls = append(calls, "closer"); return nil })).
+					Defer("second", func() error { calls = append(calls, "second"); return nil })
+		)
+
+		shutdown(0)
+		got := sut.Run()
+
+		assert.Equal(t, []string{"second", "closer", "first"}, calls)
+		assert.Equal(t, 0, got)
+	})
+
+	t.Run("should run after shutdown hooks once components are closed", func(t *testing.T) {
+		var (
+			calls []string
+			got   []unixcycle.Result
+			sut   = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithAfterShutdown(func(r unixcycle.Result) { calls = append(calls, "hook"); got = append(got, r) }),
+			).Add("closable func", unixcycle.Closer(func() error { calls = append(calls, "closer"); return assert.AnError }))
+		)
+
+		signal := sut.Run()
+
+		assert.Equal(t, []string{"closer", "hook"}, calls)
+		require.Len(t, got, 1)
+		assert.Equal(t, signal, got[0].Signal)
+		assert.ErrorIs(t, got[0].Err, assert.AnError)
+	})
+
+	t.Run("should return the exit code mapped from the result", func(t *testing.T) {
+		var (
+			hookResult unixcycle.Result
+			sut        = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithExitCodeMapper(func(r unixcycle.Result) int {
+					if errors.Is(r.Err, unixcycle.ErrSetupTimeout) {
+						return 3
+					}
+					return 1
+				}),
+				unixcycle.WithAfterShutdown(func(r unixcycle.Result) { hookResult = r }),
+				unixcycle.WithSetupTimeout(10*time.Millisecond),
+			).Add("slow setup", unixcycle.Setup(func() error { time.Sleep(100 * time.Millisecond); return nil }))
+		)
+
+		got := sut.Run()
+
+		assert.Equal(t, 3, got)
+		assert.Equal(t, 3, hookResult.ExitCode)
+		assert.Equal(t, int(syscall.SIGALRM), hookResult.Signal, "should keep the signal")
+	})
+
+	t.Run("should log timings as JSON at debug level with the logging presets", func(t *testing.T) {
+		stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+		require.NoError(t, err)
+		defer func(original *os.File) { os.Stdout = original }(os.Stdout)
+		os.Stdout = stdout // Picked up by the default logger when the manager is created
+		sut := unixcycle.NewManager(
+			unixcycle.WithLifetime(func() int { return 0 }),
+			unixcycle.WithLogLevel(slog.LevelDebug),
+			unixcycle.WithJSONLogging(),
+		).Add("db", unixcycle.Setup(func() error { return nil }))
+
+		sut.Run()
+
+		logs, err := os.ReadFile(stdout.Name())
+		require.NoError(t, err)
+		var timings []string
+		for _, line := range strings.Split(strings.TrimSpace(string(logs)), "\n") {
+			var record struct {
+				Level     string
+				Code      unixcycle.Code
+				Operation string
+			}
+			require.NoError(t, json.Unmarshal([]byte(line), &record), "should log JSON")
+			if record.Code == unixcycle.CodeTiming {
+				assert.Equal(t, "DEBUG", record.Level)
+				timings = append(timings, record.Operation)
+			}
+		}
+		assert.Equal(t, []string{"Setup", "Setup", "Shutdown"}, timings)
+	})
+
+	t.Run("should log with the configured prefix, attribute keys and messages", func(t *testing.T) {
+		var (
+			logs bytes.Buffer
+			sut  = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithLogger(slog.New(slog.NewJSONHandler(&logs, nil))),
+				unixcycle.WithLogPrefix("lifecycle: "),
+				unixcycle.WithLogKeys(unixcycle.LogKeys{Component: "component"}),
+				unixcycle.WithoutComponentNamesInMessages(),
+			).Add("db", unixcycle.Closer(func() error { return nil }))
+		)
+
+		sut.Run()
+
+		var records []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+			var record map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &record))
+			delete(record, "time")
+			records = append(records, record)
+		}
+		assert.Contains(t, records, map[string]any{
+			"level":     "INFO",
+			"msg":       "lifecycle: Closing component",
+			"component": "db",
+			"code":      string(unixcycle.CodeCloseBegin),
+		})
+	})
+
+	t.Run("should hand components a logger tagged with their name before setup", func(t *testing.T) {
+		var (
+			logs bytes.Buffer
+			comp = &loggingComponent{}
+			sut  = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithLogger(slog.New(slog.NewJSONHandler(&logs, nil))),
+				unixcycle.WithLogKeys(unixcycle.LogKeys{Component: "component"}),
+			).Add("db", comp)
+		)
+
+		sut.Run()
+
+		var components []string
+		for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+			var record struct{ Msg, Component string }
+			require.NoError(t, json.Unmarshal([]byte(line), &record))
+			if record.Msg == "connected" {
+				components = append(components, record.Component)
+			}
+		}
+		assert.Equal(t, []string{"db"}, components, "should be set before setup")
+	})
+
+	t.Run("should emit and publish the reported resource usage", func(t *testing.T) {
+		var (
+			shutdownChan = make(chan int, 1)
+			reports      = make(chan unixcycle.Event, 1)
+			sut          = unixcycle.NewManager(
+				unixcycle.WithLifetime(manualSignal(shutdownChan)),
+				unixcycle.WithResourceUsageReporting(time.Millisecond),
+				unixcycle.WithEventHandler(func(e unixcycle.Event) {
+					if e.Kind == unixcycle.EventResourceUsage {
+						select {
+						case reports <- e:
+						default:
+						}
+					}
+				}),
+			).Add("worker", unixcycle.Starter(func() error { select {} }))
+			done = make(chan int)
+		)
+		go func() { done <- sut.Run() }()
+
+		report := <-reports
+		shutdownChan <- 0
+		assert.Equal(t, 0, <-done)
+
+		require.NotNil(t, report.Usage)
+		assert.Equal(t, unixcycle.CodeResourceUsage, report.Code)
+		assert.Contains(t, report.Usage.Goroutines, "worker")
+		published := expvar.Get("unixcycle_resource_usage")
+		require.NotNil(t, published)
+		assert.Contains(t, published.String(), "TotalGoroutines")
+	})
+
+	t.Run("should attribute goroutines spawned from start to the component", func(t *testing.T) {
+		var (
+			m, shutdown = newManager()
+			stop        = make(chan struct{})
+			startable   = func() error {
+				for range 2 {
+					go func() { <-stop }()
+				}
+				<-stop
+				return nil
+			}
+			sut  = m.Add("leaky", unixcycle.Starter(startable)).Add("idle", unixcycle.Starter(func() error { return nil }))
+			done = make(chan int)
+		)
+		go func() { done <- sut.Run() }()
+
+		assert.Eventually(t, func() bool {
+			return sut.ResourceUsage().Goroutines["leaky"] == 3
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, 0, sut.ResourceUsage().Goroutines["idle"])
+
+		close(stop)
+		shutdown(0)
+		assert.Equal(t, 0, <-done)
+	})
+
+	t.Run("should log stable codes for lifecycle events", func(t *testing.T) {
+		var (
+			logs bytes.Buffer
+			sut  = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithLogger(slog.New(slog.NewJSONHandler(&logs, nil))),
+			).Add("closable func", unixcycle.Closer(func() error { return assert.AnError }))
+		)
+
+		sut.Run()
+
+		var codes []unixcycle.Code
+		for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+			var record struct{ Code unixcycle.Code }
+			require.NoError(t, json.Unmarshal([]byte(line), &record))
+			codes = append(codes, record.Code)
+		}
+		assert.Equal(t, []unixcycle.Code{unixcycle.CodeStartBegin, unixcycle.CodeStarted, unixcycle.CodeSignalReceived, unixcycle.CodeCloseBegin, unixcycle.CodeCloseFailed}, codes)
+	})
+
+	t.Run("should diagnose a close blocked on a start goroutine that already returned", func(t *testing.T) {
+		var (
+			logs    bytes.Buffer
+			stopped = make(chan struct{})
+			comp    = &testComponent{
+				setupFunc: func() error { return nil },
+				startFunc: func() error { return assert.AnError }, // Abandons stopped
+				closeFunc: func() error { stopped <- struct{}{}; return nil },
+			}
+			sut = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithCloseTimeout(100*time.Millisecond),
+				unixcycle.WithLogger(slog.New(slog.NewJSONHandler(&logs, nil))),
+			).Add("deadlocked", comp)
+		)
+
+		got := sut.Run()
+
+		assert.Equal(t, int(syscall.SIGALRM), got)
+		assert.Contains(t, logs.String(), string(unixcycle.CodeCloseDeadlock))
+		assert.Contains(t, logs.String(), "testComponent).Close")
+	})
+
+	t.Run("should keep serving during the shutdown delay", func(t *testing.T) {
+		var (
+			shutdownChan = make(chan int, 1)
+			servedFor    time.Duration
+			runnable     = func(ctx context.Context) error {
+				signalled := time.Now()
+				shutdownChan <- 0
+				<-ctx.Done()
+				servedFor = time.Since(signalled)
+				return nil
+			}
+			sut = unixcycle.NewManager(
+				unixcycle.WithLifetime(manualSignal(shutdownChan)),
+				unixcycle.WithShutdownDelay(50*time.Millisecond),
+			).
+				Add("runnable func", unixcycle.Runner(runnable))
+		)
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, 0, got.Signal)
+		assert.GreaterOrEqual(t, servedFor, 50*time.Millisecond)
+		assert.GreaterOrEqual(t, got.ShutdownDuration, 50*time.Millisecond)
+	})
+
+	t.Run("should pause between closing consecutive components", func(t *testing.T) {
+		var (
+			closedAt []time.Time
+			closer   = func() error { closedAt = append(closedAt, time.Now()); return nil }
+			sut      = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithCloseGap(50*time.Millisecond),
+			).
+				Add("first", unixcycle.Closer(closer)).
+				Add("second", unixcycle.Closer(closer))
+		)
+
+		got := sut.Run()
+
+		assert.Equal(t, 0, got)
+		require.Len(t, closedAt, 2)
+		assert.GreaterOrEqual(t, closedAt[1].Sub(closedAt[0]), 50*time.Millisecond)
+	})
+
+	t.Run("should cancel the context of runnable components before closing", func(t *testing.T) {
+		var (
+			m, shutdown = newManager()
+			calls       []string
+			runnable    = func(ctx context.Context) error {
+				shutdown(0)
+				<-ctx.Done()
+				calls = append(calls, "run cancelled")
+				return ctx.Err()
+			}
+			sut = m.
+				Add("closable func", unixcycle.Closer(func() error { calls = append(calls, "closer"); return nil })).
+				Add("runnable func", unixcycle.Runner(runnable))
+		)
+
+		got := sut.Run()
+
+		assert.Equal(t, []string{"run cancelled", "closer"}, calls)
+		assert.Equal(t, 0, got, "returning the cancelled context error should not count as a failure")
+	})
+
+	t.Run("should order components by their dependencies", func(t *testing.T) {
+		var (
+			m, shutdown = newManager()
+			calls       []string
+			newComp     = func(name string) *testComponent {
+				return &testComponent{
+					setupFunc: func() error { calls = append(calls, "setup "+name); return nil },
+					startFunc: func() error { return nil },
+					closeFunc: func() error { calls = append(calls, "close "+name); return nil },
+				}
+			}
+			sut = m.
+				Add("api", newComp("api"), unixcycle.DependsOn("db", "cache")).
+				Add("cache", newComp("cache"), unixcycle.DependsOn("db")).
+				Add("db", newComp("db"))
+		)
+
+		shutdown(0)
+		got := sut.Run()
+
+		assert.Equal(t, 0, got)
+		assert.Equal(t, []string{"setup db", "setup cache", "setup api", "close api", "close cache", "close db"}, calls)
+	})
+
+	t.Run("should order components by their priority", func(t *testing.T) {
+		var (
+			m, shutdown = newManager()
+			calls       []string
+			newComp     = func(name string) *testComponent {
+				return &testComponent{
+					setupFunc: func() error { calls = append(calls, "setup "+name); return nil },
+					startFunc: func() error { return nil },
+					closeFunc: func() error { calls = append(calls, "close "+name); return nil },
+				}
+			}
+			sut = m.
+				Add("api", newComp("api"), unixcycle.Priority(10)).
+				Add("metrics", newComp("metrics")).
+				Add("cache", newComp("cache"), unixcycle.Priority(-1), unixcycle.DependsOn("db")).
+				Add("db", newComp("db"), unixcycle.Priority(5))
+		)
+
+		shutdown(0)
+		got := sut.Run()
+
+		assert.Equal(t, 0, got)
+		assert.Equal(t, []string{
+			"setup metrics", "setup db", "setup cache", "setup api",
+			"close api", "close cache", "close db", "close metrics",
+		}, calls, "dependencies should come before priorities")
+	})
+
+	t.Run("should start a priority only once the components with lower priorities are ready", func(t *testing.T) {
+		var (
+			shutdownChan = make(chan int, 1)
+			calls        = make(chan string, 3)
+			sut          = unixcycle.NewManager(unixcycle.WithLifetime(manualSignal(shutdownChan))).
+					Add("server", unixcycle.Starter(func() error { calls <- "start server"; shutdownChan <- 0; return nil }), unixcycle.Priority(1)).
+					Add("db", newReadyComponent(calls, nil))
+		)
+
+		got := sut.Run()
+
+		assert.Equal(t, 0, got)
+		assert.Equal(t, "start db", <-calls)
+		assert.Equal(t, "db ready", <-calls)
+		assert.Equal(t, "start server", <-calls)
+	})
+
+	t.Run("should close components by their close order", func(t *testing.T) {
+		for _, concurrent := range []bool{false, true} {
+			var (
+				shutdownChan = make(chan int, 1)
+				mu           sync.Mutex
+				calls        []string
+				newComp      = func(name string) unixcycle.Component {
+					return unixcycle.Closer(func() error {
+						mu.Lock()
+						defer mu.Unlock()
+						calls = append(calls, name)
+						return nil
+					})
+				}
+				closeOption = unixcycle.WithCloseGap(0)
+			)
+			if concurrent {
+				closeOption = unixcycle.WithConcurrentClose()
+			}
+			sut := unixcycle.NewManager(unixcycle.WithLifetime(manualSignal(shutdownChan)), closeOption).
+				Add("flusher", newComp("flusher"), unixcycle.CloseLast()).
+				Add("db", newComp("db")).
+				Add("api", newComp("api"), unixcycle.DependsOn("db"), unixcycle.CloseOrder(1)).
+				Add("cache", newComp("cache"), unixcycle.CloseOrder(-1))
+
+			shutdownChan <- 0
+			got := sut.Run()
+
+			assert.Equal(t, 0, got)
+			if concurrent {
+				assert.Equal(t, "flusher", calls[len(calls)-1], "the flusher should be closed last")
+				assert.Less(t, slices.Index(calls, "api"), slices.Index(calls, "db"), "api depends on db")
+			} else {
+				assert.Equal(t, []string{"cache", "api", "db", "flusher"}, calls)
+			}
+		}
+	})
+
+	t.Run("should close independent components concurrently after their dependents", func(t *testing.T) {
+		var (
+			shutdownChan = make(chan int, 1)
+			closing      sync.WaitGroup
+			apiClosed    atomic.Bool
+			independent  = func() error {
+				if !apiClosed.Load() {
+					return errors.New("closed before the api depending on it")
+				}
+				closing.Done()
+				closing.Wait() // Only returns once both are closing at the same time
+				return nil
+			}
+			sut = unixcycle.NewManager(
+				unixcycle.WithLifetime(manualSignal(shutdownChan)),
+				unixcycle.WithCloseTimeout(time.Second),
+				unixcycle.WithConcurrentClose(),
+			).
+				Add("db", unixcycle.Closer(independent)).
+				Add("cache", unixcycle.Closer(independent)).
+				Add("api", unixcycle.Closer(func() error { apiClosed.Store(true); return nil }), unixcycle.DependsOn("db", "cache"))
+		)
+		closing.Add(2)
+
+		shutdownChan <- 0
+		got := sut.RunWithResult()
+
+		assert.Equal(t, 0, got.Signal)
+		assert.NoError(t, got.Err)
+	})
+
+	t.Run("should close concurrently when a close order and a dependency point in opposite directions", func(t *testing.T) {
+		var (
+			shutdownChan = make(chan int, 1)
+			mu           sync.Mutex
+			calls        []string
+			newComp      = func(name string) unixcycle.Component {
+				return unixcycle.Closer(func() error {
+					mu.Lock()
+					defer mu.Unlock()
+					calls = append(calls, name)
+					return nil
+				})
+			}
+			sut = unixcycle.NewManager(
+				unixcycle.WithLifetime(manualSignal(shutdownChan)),
+				unixcycle.WithCloseTimeout(time.Second),
+				unixcycle.WithConcurrentClose(),
+			).
+				Add("a", newComp("a")).
+				Add("b", newComp("b"), unixcycle.CloseOrder(1)).
+				Add("c", newComp("c"), unixcycle.CloseOrder(2), unixcycle.DependsOn("a"))
+			result = make(chan unixcycle.Result, 1)
+		)
+		require.NoError(t, sut.Validate())
+
+		shutdownChan <- 0
+		go func() { result <- sut.RunWithResult() }()
+
+		select {
+		case got := <-result:
+			assert.NoError(t, got.Err)
+			assert.Equal(t, []string{"b", "c", "a"}, calls)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "shutdown should not hang")
+		}
+	})
+
+	t.Run("should receive SIGABRT on dependency cycles and unknown dependencies", func(t *testing.T) {
+		for name, options := range map[string][2][]string{
+			"cycle":   {{"b"}, {"a"}},
+			"unknown": {{"c"}, nil},
+		} {
+			t.Run(name, func(t *testing.T) {
+				var (
+					m, _       = newManager()
+					setupCalls = 0
+					setup      = unixcycle.Setup(func() error { setupCalls++; return nil })
+					sut        = m.
+							Add("a", setup, unixcycle.DependsOn(options[0]...)).
+							Add("b", setup, unixcycle.DependsOn(options[1]...))
+				)
+
+				got := sut.Run()
+
+				assert.Equal(t, int(syscall.SIGABRT), got)
+				assert.Equal(t, 0, setupCalls)
+			})
+		}
+	})
+
+	t.Run("should reject duplicate names and nil components", func(t *testing.T) {
+		var (
+			m, _         = newManager()
+			setupCalls   = 0
+			setup        = unixcycle.Setup(func() error { setupCalls++; return nil })
+			nilComponent *testComponent
+			sut          = m.
+					Add("db", setup).
+					Add("db", setup).
+					Add("broken", nilComponent)
+		)
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, int(syscall.SIGABRT), got.Signal)
+		assert.Equal(t, unixcycle.PhaseAdd, got.FailedPhase)
+		assert.ErrorContains(t, got.Err, `duplicate component name "db"`)
+		assert.ErrorContains(t, got.Err, `component "broken" is nil`)
+		assert.Equal(t, 0, setupCalls)
+		assert.Panics(t, func() { sut.MustAdd("db", setup) })
+	})
+
+	t.Run("should close components that were set up when a later setup fails", func(t *testing.T) {
+		var (
+			m, _    = newManager()
+			calls   []string
+			newComp = func(name string, setupErr error) *testComponent {
+				return &testComponent{
+					setupFunc: func() error { calls = append(calls, "setup "+name); return setupErr },
+					startFunc: func() error { calls = append(calls, "start "+name); return nil },
+					closeFunc: func() error { calls = append(calls, "close "+name); return nil },
+				}
+			}
+			sut = m.
+				Add("first", newComp("first", nil)).
+				Add("second", newComp("second", nil)).
+				Add("failing", newComp("failing", assert.AnError)).
+				Add("never set up", newComp("never set up", nil))
+		)
+
+		got := sut.Run()
+
+		assert.Equal(t, int(syscall.SIGABRT), got)
+		assert.Equal(t, []string{"setup first", "setup second", "setup failing", "close second", "close first"}, calls)
+	})
+
+	t.Run("should use per component timeouts over the manager timeouts", func(t *testing.T) {
+		var (
+			m, shutdown = newManager()
+			slow        = func() error { time.Sleep(200 * time.Millisecond); return nil } // Slower than the 100ms timeout
+			sut         = m.
+					Add("slow setup", unixcycle.Setup(slow), unixcycle.WithComponentSetupTimeout(time.Second)).
+					Add("slow close", unixcycle.Closer(slow), unixcycle.WithComponentCloseTimeout(time.Second))
+		)
+
+		shutdown(0)
+		got := sut.Run()
+
+		assert.Equal(t, 0, got)
+	})
+
+	t.Run("should bound the total close time by the shutdown budget", func(t *testing.T) {
+		var (
+			closes atomic.Int32
+			slow   = func() error { closes.Add(1); time.Sleep(80 * time.Millisecond); return nil } // Within the 100ms timeout each
+			sut    = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithCloseTimeout(100*time.Millisecond),
+				unixcycle.WithShutdownBudget(150*time.Millisecond),
+			).
+				Add("first", unixcycle.Closer(slow)).
+				Add("second", unixcycle.Closer(slow)).
+				Add("third", unixcycle.Closer(slow))
+		)
+
+		start := time.Now()
+		got := sut.Run()
+
+		assert.Equal(t, int(syscall.SIGALRM), got)
+		assert.Less(t, time.Since(start), 200*time.Millisecond)
+		assert.Equal(t, int32(2), closes.Load(), "the third close should not be reached")
+	})
+
+	t.Run("should hand the remaining close deadline to components closing with a context", func(t *testing.T) {
+		var (
+			deadlines = map[string]time.Duration{}
+			closer    = func(name string, work time.Duration) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					deadline, ok := ctx.Deadline()
+					require.True(t, ok)
+					deadlines[name] = time.Until(deadline)
+					time.Sleep(work)
+					return nil
+				}
+			}
+			sut = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithCloseTimeout(time.Second),
+				unixcycle.WithShutdownBudget(150*time.Millisecond),
+			).
+				Add("first", unixcycle.CloserContext(closer("first", 0)), unixcycle.WithComponentCloseTimeout(20*time.Millisecond)).
+				Add("second", unixcycle.CloserContext(closer("second", 80*time.Millisecond)))
+		)
+
+		got := sut.Run()
+
+		assert.Equal(t, 0, got)
+		assert.InDelta(t, 150*time.Millisecond, deadlines["second"], float64(20*time.Millisecond), "should expire with the shutdown budget")
+		assert.InDelta(t, 20*time.Millisecond, deadlines["first"], float64(10*time.Millisecond), "should expire with the close timeout of the component")
+	})
+
+	t.Run("should track a close still running after timing out until it returns", func(t *testing.T) {
+		var (
+			logs    syncWriter
+			release = make(chan struct{})
+			sut     = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithCloseTimeout(10*time.Millisecond),
+				unixcycle.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+			).Add("stuck", unixcycle.Closer(func() error { <-release; return nil }))
+		)
+
+		got := sut.Run()
+
+		assert.Equal(t, int(syscall.SIGALRM), got)
+		assert.Equal(t, 1, sut.ResourceUsage().TimedOut)
+		assert.Contains(t, logs.String(), string(unixcycle.CodeTimedOutRunning))
+		close(release)
+		assert.Eventually(t, func() bool { return sut.ResourceUsage().TimedOut == 0 }, time.Second, time.Millisecond)
+		assert.Eventually(t, func() bool { return strings.Contains(logs.String(), string(unixcycle.CodeTimedOutReturned)) }, time.Second, time.Millisecond)
+	})
+
+	t.Run("should report the failing phase and component in the result", func(t *testing.T) {
+		var (
+			m, _ = newManager()
+			sut  = m.
+				Add("healthy", unixcycle.Setup(func() error { return nil })).
+				Add("broken", unixcycle.Starter(func() error { return assert.AnError }))
+		)
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, int(syscall.SIGABRT), got.Signal)
+		assert.Equal(t, unixcycle.PhaseStart, got.FailedPhase)
+		assert.ErrorIs(t, got.Err, assert.AnError)
+		assert.ErrorIs(t, got.Err, unixcycle.ErrStartFailed)
+		var componentErr *unixcycle.ComponentError
+		require.ErrorAs(t, got.Err, &componentErr)
+		assert.Equal(t, "broken", componentErr.Component)
+		assert.Equal(t, map[string]error{"broken": assert.AnError}, got.ComponentErrors)
+		assert.Positive(t, got.ShutdownDuration)
+	})
+
+	t.Run("should report setup as the failing phase", func(t *testing.T) {
+		var (
+			m, _ = newManager()
+			sut  = m.Add("broken", unixcycle.Setup(func() error { return assert.AnError }))
+		)
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, int(syscall.SIGABRT), got.Signal)
+		assert.Equal(t, unixcycle.PhaseSetup, got.FailedPhase)
+		assert.ErrorIs(t, got.Err, unixcycle.ErrSetupFailed)
+		assert.Equal(t, map[string]error{"broken": assert.AnError}, got.ComponentErrors)
+	})
+
+	t.Run("should report timeouts as sentinel errors", func(t *testing.T) {
+		var (
+			m, shutdown = newManager()
+			slow        = func() error { time.Sleep(200 * time.Millisecond); return nil } // Slower than the 100ms timeout
+			sut         = m.Add("slow close", unixcycle.Closer(slow))
+		)
+
+		shutdown(0)
+		got := sut.RunWithResult()
+
+		assert.Equal(t, int(syscall.SIGALRM), got.Signal)
+		assert.ErrorIs(t, got.Err, unixcycle.ErrCloseTimeout)
+		assert.ErrorContains(t, got.Err, `component "slow close": close timed out`)
+	})
+
+	t.Run("should shut down gracefully when the run context is cancelled", func(t *testing.T) {
+		var (
+			m, _        = newManager()
+			ctx, cancel = context.WithCancel(context.Background())
+			closeCalled = false
+			sut         = m.
+					Add("startable func", unixcycle.Starter(func() error { cancel(); return nil })).
+					Add("closable func", unixcycle.Closer(func() error { closeCalled = true; return nil }))
+		)
+
+		got := sut.RunContext(ctx)
+
+		assert.Equal(t, 0, got)
+		assert.True(t, closeCalled)
+	})
+
+	t.Run("should start a startup group only once the previous group is ready", func(t *testing.T) {
+		var (
+			shutdownChan = make(chan int, 1)
+			calls        = make(chan string, 3)
+			sut          = unixcycle.NewManager(
+				unixcycle.WithLifetime(manualSignal(shutdownChan)),
+				unixcycle.WithStartupGroups("infrastructure", "servers"),
+			).
+				Add("server", unixcycle.Starter(func() error { calls <- "start server"; shutdownChan <- 0; return nil }), unixcycle.InGroup("servers")).
+				Add("db", newReadyComponent(calls, nil), unixcycle.InGroup("infrastructure"))
+		)
+
+		got := sut.Run()
+
+		assert.Equal(t, 0, got)
+		assert.Equal(t, "start db", <-calls)
+		assert.Equal(t, "db ready", <-calls)
+		assert.Equal(t, "start server", <-calls)
+	})
+
+	t.Run("should start the components on Start and shut them down on Wait", func(t *testing.T) {
+		var (
+			shutdownChan = make(chan int, 1)
+			started      = make(chan struct{})
+			closed       = false
+			sut          = unixcycle.NewManager(unixcycle.WithLifetime(manualSignal(shutdownChan))).
+					Add("worker", unixcycle.Starter(func() error { close(started); return nil })).
+					Add("closer", unixcycle.Closer(func() error { closed = true; return nil }))
+		)
+
+		require.NoError(t, sut.Start())
+		<-started
+		assert.False(t, closed, "should not shut down before Wait")
+		assert.ErrorIs(t, sut.Start(), unixcycle.ErrAlreadyRunning, "should not start twice")
+		shutdownChan <- 0
+		got := sut.Wait()
+
+		assert.Equal(t, 0, got.Signal)
+		assert.NoError(t, got.Err)
+		assert.True(t, closed)
+	})
+
+	t.Run("should refuse to run twice or add components after starting", func(t *testing.T) {
+		var (
+			shutdownChan = make(chan int, 1)
+			started      = make(chan struct{})
+			sut          = unixcycle.NewManager(unixcycle.WithLifetime(manualSignal(shutdownChan))).
+					Add("worker", unixcycle.Starter(func() error { close(started); return nil }))
+			done = make(chan unixcycle.Result)
+		)
+		go func() { done <- sut.RunWithResult() }()
+		<-started
+
+		running := sut.RunWithResult()
+		sut.Add("late", unixcycle.Starter(func() error { return nil }))
+		shutdownChan <- 0
+		first := <-done
+		stopped := sut.RunWithResult()
+
+		assert.NoError(t, first.Err)
+		assert.ErrorIs(t, running.Err, unixcycle.ErrAlreadyRunning)
+		assert.Equal(t, int(syscall.SIGABRT), running.ExitCode)
+		assert.ErrorIs(t, stopped.Err, unixcycle.ErrAlreadyStopped)
+		assert.ErrorIs(t, sut.Start(), unixcycle.ErrAlreadyStopped)
+		assert.Panics(t, func() { sut.MustAdd("late", unixcycle.Starter(func() error { return nil })) })
+		for c := range sut.Components() {
+			assert.NotEqual(t, "late", c.Name, "should not add components after starting")
+		}
+	})
+
+	t.Run("should run again once reset", func(t *testing.T) {
+		var (
+			shutdownChan = make(chan int, 1)
+			closed       []string
+			sut          = unixcycle.NewManager(unixcycle.WithLifetime(manualSignal(shutdownChan)))
+			newCloser    = func(name string) unixcycle.Component {
+				return unixcycle.Closer(func() error { closed = append(closed, name); return nil })
+			}
+		)
+		sut.Add("first", newCloser("first"))
+		shutdownChan <- 0
+		first := sut.RunWithResult()
+
+		require.NoError(t, sut.Reset())
+		sut.Add("second", newCloser("second"))
+		shutdownChan <- int(syscall.SIGTERM)
+		second := sut.RunWithResult()
+
+		assert.NoError(t, first.Err)
+		assert.NoError(t, second.Err)
+		assert.Equal(t, int(syscall.SIGTERM), second.Signal)
+		assert.Equal(t, []string{"first", "second"}, closed, "reset should remove the components of the previous run")
+	})
+
+	t.Run("should not reset a running manager", func(t *testing.T) {
+		var (
+			shutdownChan = make(chan int, 1)
+			sut          = unixcycle.NewManager(unixcycle.WithLifetime(manualSignal(shutdownChan)))
+		)
+		require.NoError(t, sut.Start())
+
+		err := sut.Reset()
+		shutdownChan <- 0
+		sut.Wait()
+
+		assert.ErrorIs(t, err, unixcycle.ErrAlreadyRunning)
+	})
+
+	t.Run("should return the failed startup from Start and Wait", func(t *testing.T) {
+		var (
+			m, _ = newManager()
+			sut  = m.Add("failing setup", unixcycle.Setup(func() error { return assert.AnError }))
+		)
+
+		err := sut.Start()
+		got := sut.Wait()
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorIs(t, got.Err, assert.AnError)
+		assert.Equal(t, unixcycle.PhaseSetup, got.FailedPhase)
+	})
+
+	t.Run("should start a component only once the components it depends on are ready", func(t *testing.T) {
+		var (
+			shutdownChan = make(chan int, 1)
+			calls        = make(chan string, 3)
+			sut          = unixcycle.NewManager(unixcycle.WithLifetime(manualSignal(shutdownChan))).
+					Add("server", unixcycle.Starter(func() error { calls <- "start server"; shutdownChan <- 0; return nil }), unixcycle.DependsOn("db")).
+					Add("db", newReadyComponent(calls, nil))
+		)
+
+		got := sut.Run()
+
+		assert.Equal(t, 0, got)
+		assert.Equal(t, "start db", <-calls)
+		assert.Equal(t, "db ready", <-calls)
+		assert.Equal(t, "start server", <-calls)
+	})
+
+	t.Run("should not start a component when a component it depends on does not become ready", func(t *testing.T) {
+		var (
+			serverStarted = false
+			sut           = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).
+					Add("db", newReadyComponent(make(chan string, 2), errors.New("no connection"))).
+					Add("server", unixcycle.Starter(func() error { serverStarted = true; return nil }), unixcycle.DependsOn("db"))
+		)
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, int(syscall.SIGABRT), got.Signal)
+		assert.ErrorIs(t, got.Err, unixcycle.ErrNotReady)
+		assert.False(t, serverStarted)
+	})
+
+	t.Run("should start a component only once the readiness prober of its dependency succeeded", func(t *testing.T) {
+		var (
+			shutdownChan = make(chan int, 1)
+			calls        = make(chan string, 4)
+			probes       atomic.Int32
+			prober       = unixcycle.RetryingProber(time.Millisecond, time.Second, func(ctx context.Context) error {
+				if probes.Add(1) < 3 {
+					return errors.New("not accepting connections yet")
+				}
+				calls <- "db probed"
+				return nil
+			})
+			sut = unixcycle.NewManager(unixcycle.WithLifetime(manualSignal(shutdownChan))).
+				Add("server", unixcycle.Starter(func() error { calls <- "start server"; shutdownChan <- 0; return nil }), unixcycle.DependsOn("db")).
+				Add("db", newReadyComponent(calls, nil), unixcycle.WithReadiness(prober))
+		)
+
+		got := sut.Run()
+
+		assert.Equal(t, 0, got)
+		assert.Equal(t, "start db", <-calls)
+		assert.Equal(t, "db ready", <-calls, "should run the prober after Ready")
+		assert.Equal(t, "db probed", <-calls)
+		assert.Equal(t, "start server", <-calls)
+		assert.EqualValues(t, 3, probes.Load())
+	})
+
+	t.Run("should not start a component when the readiness prober of its dependency fails", func(t *testing.T) {
+		var (
+			serverStarted = false
+			notReady      = func(ctx context.Context) error { return errors.New("no connection") }
+			sut           = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).
+					Add("db", unixcycle.Runner(func(ctx context.Context) error { <-ctx.Done(); return nil }), unixcycle.WithReadiness(notReady)).
+					Add("server", unixcycle.Starter(func() error { serverStarted = true; return nil }), unixcycle.DependsOn("db"))
+		)
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, int(syscall.SIGABRT), got.Signal)
+		assert.ErrorIs(t, got.Err, unixcycle.ErrNotReady)
+		assert.ErrorContains(t, got.Err, `component "db"`)
+		assert.ErrorContains(t, got.Err, "no connection")
+		assert.False(t, serverStarted)
+	})
+
+	t.Run("should not start later startup groups when a component does not become ready", func(t *testing.T) {
+		var (
+			serverStarted = false
+			sut           = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithStartupGroups("infrastructure", "servers"),
+			).
+				Add("db", newReadyComponent(make(chan string, 2), errors.New("no connection")), unixcycle.InGroup("infrastructure")).
+				Add("server", unixcycle.Starter(func() error { serverStarted = true; return nil }), unixcycle.InGroup("servers"))
+		)
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, int(syscall.SIGABRT), got.Signal)
+		assert.ErrorIs(t, got.Err, unixcycle.ErrNotReady)
+		assert.False(t, serverStarted)
+	})
+
+	t.Run("should receive SIGABRT when a component is in an unknown startup group", func(t *testing.T) {
+		var (
+			m, _ = newManager()
+			sut  = m.Add("server", unixcycle.Starter(func() error { return nil }), unixcycle.InGroup("servers"))
+		)
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, int(syscall.SIGABRT), got.Signal)
+		assert.Equal(t, unixcycle.PhaseDependencies, got.FailedPhase)
+	})
+}
+
+type loggingComponent struct {
+	logger *slog.Logger
+}
+
+func (c *loggingComponent) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
+func (c *loggingComponent) Setup() error {
+	c.logger.Info("connected")
+	return nil
+}
+
+func (c *loggingComponent) Start() error {
+	return nil
+}
+
+type readyComponent struct {
+	calls    chan string
+	started  chan struct{}
+	readyErr error
+}
+
+func newReadyComponent(calls chan string, readyErr error) *readyComponent {
+	return &readyComponent{calls: calls, started: make(chan struct{}), readyErr: readyErr}
+}
+
+func (c *readyComponent) Start() error {
+	c.calls <- "start db"
+	close(c.started)
+	return nil
+}
+
+func (c *readyComponent) Ready(ctx context.Context) error {
+	<-c.started
+	if c.readyErr != nil {
+		return c.readyErr
+	}
+	c.calls <- "db ready"
+	return nil
 }
 
 type testComponent struct {
@@ -197,3 +1313,79 @@ func (c *testComponent) Close() error {
 	c.closeCalledCount++
 	return c.closeFunc()
 }
+
+// contextClosingComponent stops closing gracefully once the context of CloseContext is done, like HTTPServer
+type contextClosingComponent struct{}
+
+func (c *contextClosingComponent) Start() error { return nil }
+
+func (c *contextClosingComponent) CloseContext(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+type statefulComponent struct {
+	state    []byte
+	restored []byte
+	saveErr  error
+}
+
+func (c *statefulComponent) Start() error { return nil }
+
+func (c *statefulComponent) SaveState() ([]byte, error) { return c.state, c.saveErr }
+
+func (c *statefulComponent) RestoreState(state []byte) error {
+	c.restored = state
+	return nil
+}
+
+type checkpointComponent struct {
+	name          string
+	calls         *[]string
+	checkpointErr error
+}
+
+func (c *checkpointComponent) Start() error { return nil }
+
+func (c *checkpointComponent) Checkpoint(ctx context.Context) error {
+	*c.calls = append(*c.calls, "checkpoint "+c.name)
+	return c.checkpointErr
+}
+
+func (c *checkpointComponent) Close() error {
+	*c.calls = append(*c.calls, "close "+c.name)
+	return nil
+}
+
+// drainRunnable records whether its context was still live while it was drained
+type drainRunnable struct {
+	ctx       context.Context
+	running   chan struct{}
+	drainErr  error
+	cancelled bool
+}
+
+func (r *drainRunnable) Start() error { return nil } // Run is preferred
+
+func (r *drainRunnable) Run(ctx context.Context) error {
+	r.ctx = ctx
+	close(r.running)
+	<-ctx.Done()
+	r.cancelled = true
+	return ctx.Err()
+}
+
+func (r *drainRunnable) Drain(ctx context.Context) error {
+	<-r.running
+	r.drainErr = r.ctx.Err()
+	return nil
+}
+
+type drainComponent struct {
+	checkpointComponent
+}
+
+func (c *drainComponent) Drain(ctx context.Context) error {
+	*c.calls = append(*c.calls, "drain "+c.name)
+	return nil
+}
@@ -1,17 +1,51 @@
 package unixcycle
 
 import (
+	"io"
 	"log/slog"
+	"math"
+	"os"
 	"time"
 )
 
 type managerOption func(*managerOptions)
 
 type managerOptions struct {
-	logger       *slog.Logger
-	setupTimeout time.Duration
-	closeTimeout time.Duration
-	lifetime     TerminationSignal
+	logger            *slog.Logger
+	logPrefix         string
+	logKeys           LogKeys
+	namesInMessages   bool
+	logLevel          slog.Level
+	jsonLogging       bool
+	setupTimeout      time.Duration
+	closeTimeout      time.Duration
+	checkpointTimeout time.Duration
+	drainTimeout      time.Duration
+	lifetime          TerminationSignal
+	stateFile         string
+	afterShutdown     []func(Result)
+	exitCodeMapper    func(Result) int
+	onStarted         []func(time.Duration)
+	onSetupComplete   []func()
+	onShutdownStart   []func()
+	eventHandlers     []func(Event)
+	resourceInterval  time.Duration
+	statusOnSIGINFO   bool
+	closeGap          time.Duration
+	concurrentClose   bool
+	tracer            Tracer
+	panicHandler      func(component string, recovered any, stack []byte)
+	systemdNotify     bool
+	reloadSignals     []os.Signal
+	forwardSignals    bool
+	shutdownBudget    time.Duration
+	shutdownDelay     time.Duration
+	startupGroups     []string
+	upgradeCommand    []string
+	leaderGate        LeaderGate
+	forceExitAfter    time.Duration
+	readinessProber   ProberFunc
+	supervision       supervisionOptions
 }
 
 func WithLifetime(lifetime TerminationSignal) managerOption {
@@ -38,11 +72,230 @@ func WithCloseTimeout(timeout time.Duration) managerOption {
 	}
 }
 
+// WithShutdownBudget bounds the total time spent shutting down: cancelling runnable components, draining, checkpointing and closing.
+// Every step gets the smaller of its own timeout and what is left of the budget, so the shutdown as a whole
+// fits within e.g. Kubernetes' terminationGracePeriodSeconds
+// Default is no budget, only the timeouts of EACH component apply
+func WithShutdownBudget(budget time.Duration) managerOption {
+	return func(o *managerOptions) {
+		o.shutdownBudget = budget
+	}
+}
+
+// WithShutdownDelay keeps all components running for d after the exit signal is received, before anything is drained or closed,
+// so endpoints have time to be removed from load balancers, like a Kubernetes preStop sleep. The delay counts towards
+// the shutdown budget and is skipped when the manager shuts down because a component failed
+// Default is no delay
+func WithShutdownDelay(d time.Duration) managerOption {
+	return func(o *managerOptions) {
+		o.shutdownDelay = d
+	}
+}
+
+// WithCloseGap sets a pause between closing consecutive components
+// Useful when downstream components need a moment to observe an upstream disappearing
+// (connection teardown, dns/endpoint propagation) before being closed themselves
+// Default is no pause
+func WithCloseGap(gap time.Duration) managerOption {
+	return func(o *managerOptions) {
+		o.closeGap = gap
+	}
+}
+
+// WithDrainTimeout sets the timeout that EACH component has to drain
+// when shutdown starts, before the manager will consider the drain failed
+// Default is 5 seconds
+func WithDrainTimeout(timeout time.Duration) managerOption {
+	return func(o *managerOptions) {
+		o.drainTimeout = timeout
+	}
+}
+
+// WithConcurrentClose closes components concurrently, only keeping the order declared with DependsOn:
+// a component is closed as soon as all components depending on it are closed
+// Default is closing one component at a time, in the reverse order they were set up in
+func WithConcurrentClose() managerOption {
+	return func(o *managerOptions) {
+		o.concurrentClose = true
+	}
+}
+
+// WithCheckpointTimeout sets the timeout that EACH component has to checkpoint
+// when shutdown starts, before the manager will consider the checkpoint failed
+// Default is 5 seconds
+func WithCheckpointTimeout(timeout time.Duration) managerOption {
+	return func(o *managerOptions) {
+		o.checkpointTimeout = timeout
+	}
+}
+
 // WithLogger sets the logger for the manager
 // If handler is nil, the manager will log nothing
-// Default is a text logging handler that writes to os.Stdout
+// Default is a text logging handler that writes to os.Stdout, see WithLogLevel and WithJSONLogging
 func WithLogger(logger *slog.Logger) managerOption {
 	return func(o *managerOptions) {
+		if logger == nil {
+			logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+		}
 		o.logger = logger
 	}
 }
+
+// WithLogLevel sets the minimum level of the default logger, e.g. slog.LevelDebug to also log
+// how long every component took to set up, drain, checkpoint and close. Has no effect together with WithLogger
+// Default is slog.LevelInfo
+func WithLogLevel(level slog.Level) managerOption {
+	return func(o *managerOptions) {
+		o.logLevel = level
+	}
+}
+
+// WithJSONLogging makes the default logger write JSON to os.Stdout instead of text. Has no effect together with WithLogger
+func WithJSONLogging() managerOption {
+	return func(o *managerOptions) {
+		o.jsonLogging = true
+	}
+}
+
+// WithStateFile sets the file used to persist the state of components implementing StateSaver
+// The state is written during shutdown and read back before setup on the next run
+// Default is no state file, in which case StateSaver components are not persisted
+func WithStateFile(path string) managerOption {
+	return func(o *managerOptions) {
+		o.stateFile = path
+	}
+}
+
+// WithAfterShutdown registers a hook that runs after all components are closed, but before Run returns
+// Useful for final tasks like flushing telemetry exporters or notifying a supervisor
+// Hooks are run in the order they are registered and can be registered multiple times
+func WithAfterShutdown(hook func(Result)) managerOption {
+	return func(o *managerOptions) {
+		o.afterShutdown = append(o.afterShutdown, hook)
+	}
+}
+
+// WithResourceUsageReporting logs a snapshot of the goroutines per component and the heap usage every interval
+// while the components are running. Every snapshot is also emitted as EventResourceUsage and published with expvar
+// as "unixcycle_resource_usage", see DebugServer. See Manager.ResourceUsage
+// Default is no periodic reporting
+func WithResourceUsageReporting(interval time.Duration) managerOption {
+	return func(o *managerOptions) {
+		o.resourceInterval = interval
+	}
+}
+
+// WithStatusOnSIGINFO prints a status summary of the components, their states and uptimes to stderr
+// whenever SIGINFO (Ctrl+T) is received, like well-behaved BSD daemons do
+// Only has an effect on platforms that have SIGINFO (darwin and the BSDs)
+func WithStatusOnSIGINFO() managerOption {
+	return func(o *managerOptions) {
+		o.statusOnSIGINFO = true
+	}
+}
+
+// WithStartupGroups declares the groups components are started in, in order. See InGroup
+// Within a group, components are launched concurrently as far as DependsOn and Priority allow: a component only once
+// its dependencies are ready. The next group is only launched once every component of the group is ready, meaning
+// Ready(ctx) error and the prober of WithReadiness returned within its setup timeout, or launched if it has neither.
+// Components without a group are started after all groups. The groups start in the background, so a signal interrupts
+// the startup, and WithReadinessProber only runs after the last group. Groups don't change the close order,
+// see WithConcurrentClose
+// Default is no groups, all components are launched at once
+func WithStartupGroups(groups ...string) managerOption {
+	return func(o *managerOptions) {
+		o.startupGroups = append(o.startupGroups, groups...)
+	}
+}
+
+type componentOption func(*componentOptions)
+
+type componentOptions struct {
+	tags         []string
+	dependsOn    []string
+	group        string
+	setupTimeout time.Duration
+	closeTimeout time.Duration
+	onExit       ExitPolicy
+	onPanic      PanicPolicy
+	leaderOnly   bool
+	priority     int
+	closeOrder   int
+	readiness    ProberFunc
+}
+
+// Tags attaches free-form tags to a component, e.g. to group components in external tooling
+// See Manager.Components
+func Tags(tags ...string) componentOption {
+	return func(o *componentOptions) {
+		o.tags = append(o.tags, tags...)
+	}
+}
+
+// DependsOn declares that a component depends on the components with the given names
+// The component is set up and started after its dependencies, and closed before them, regardless of the order they were added in
+func DependsOn(names ...string) componentOption {
+	return func(o *componentOptions) {
+		o.dependsOn = append(o.dependsOn, names...)
+	}
+}
+
+// WithComponentSetupTimeout overrides the setup timeout of the manager for a single component
+// See WithSetupTimeout
+func WithComponentSetupTimeout(timeout time.Duration) componentOption {
+	return func(o *componentOptions) {
+		o.setupTimeout = timeout
+	}
+}
+
+// WithComponentCloseTimeout overrides the close timeout of the manager for a single component
+// See WithCloseTimeout
+func WithComponentCloseTimeout(timeout time.Duration) componentOption {
+	return func(o *componentOptions) {
+		o.closeTimeout = timeout
+	}
+}
+
+// Priority controls the start order independently of the order components are added in: components are set up and
+// started by ascending priority, and closed in reverse. Components with the same priority are started without waiting
+// for each other, a component with a higher priority only once all components with lower priorities are ready.
+// Dependencies declared with DependsOn come first, and startup groups start group by group regardless of priorities
+// Default is 0
+func Priority(priority int) componentOption {
+	return func(o *componentOptions) {
+		o.priority = priority
+	}
+}
+
+// CloseOrder controls the close order independently of the start order: components are closed by ascending close order,
+// and in the reverse order they were set up in within the same close order. A component is still never closed
+// before the components depending on it, see DependsOn
+// Default is 0
+func CloseOrder(order int) componentOption {
+	return func(o *componentOptions) {
+		o.closeOrder = order
+	}
+}
+
+// CloseLast closes a component after all others, regardless of when it was added, e.g. a metrics flusher or a log sink.
+// Like CloseOrder with the highest order
+func CloseLast() componentOption {
+	return CloseOrder(math.MaxInt)
+}
+
+// WithReadiness makes the manager wait for prober to succeed once the component is started, like for components
+// implementing Ready(ctx) error: before starting the components depending on it, the next priority and the next startup
+// group, and before the manager is considered started. It runs after Ready if the component implements it,
+// within the setup timeout of the component, and a failing prober shuts the manager down with ErrNotReady
+func WithReadiness(prober ProberFunc) componentOption {
+	return func(o *componentOptions) {
+		o.readiness = prober
+	}
+}
+
+// InGroup puts a component into one of the startup groups declared with WithStartupGroups
+func InGroup(group string) componentOption {
+	return func(o *componentOptions) {
+		o.group = group
+	}
+}
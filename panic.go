@@ -0,0 +1,100 @@
+package unixcycle
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// panicError is a recovered panic, with the stack of the goroutine that panicked
+type panicError struct {
+	value any
+	stack []byte
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+// Unwrap returns the panic value if it is an error, e.g. to match a panic(ErrSomething) with errors.Is
+func (e *panicError) Unwrap() error {
+	err, _ := e.value.(error)
+	return err
+}
+
+// WithPanicHandler calls handler with the recovered value and the full stack trace whenever the Start of a component panics,
+// e.g. to ship it to crash reporting. It is called before the panic policy of the component is applied, see OnPanic
+// Default is only logging the recovered value
+func WithPanicHandler(handler func(component string, recovered any, stack []byte)) managerOption {
+	return func(o *managerOptions) {
+		o.panicHandler = handler
+	}
+}
+
+// handlePanic passes a recovered panic to the panic handler, if there is one
+func (m *Manager) handlePanic(s namedComponent, err error) {
+	var panicErr *panicError
+	if m.panicHandler != nil && errors.As(err, &panicErr) {
+		m.panicHandler(s.name, panicErr.value, panicErr.stack)
+	}
+}
+
+// PanicAction is what happens to a component whose Start panicked, see PanicPolicy
+type PanicAction int
+
+const (
+	// PanicSupervise treats the panic like any other failure, applying the supervision strategy
+	PanicSupervise PanicAction = iota
+	// PanicAbort shuts the manager down with SIGABRT, even with supervision
+	PanicAbort
+	// PanicRestart starts the component again with the supervision backoff (one second without supervision), without ever aborting
+	PanicRestart
+	// PanicIgnore leaves the component failed while the other components keep running
+	PanicIgnore
+)
+
+// PanicPolicy decides what happens when the Start of a component panics, given the recovered panic as error
+type PanicPolicy func(err error) PanicAction
+
+// AbortOnPanic is a PanicPolicy shutting the manager down on any panic
+func AbortOnPanic(error) PanicAction { return PanicAbort }
+
+// RestartOnPanic is a PanicPolicy restarting the component on any panic, e.g. for a flaky non-critical worker
+func RestartOnPanic(error) PanicAction { return PanicRestart }
+
+// OnPanic sets what happens when Start of the component panics.
+// Default is PanicSupervise: SIGABRT without supervision, a restart with supervision
+func OnPanic(policy PanicPolicy) componentOption {
+	return func(o *componentOptions) {
+		o.onPanic = policy
+	}
+}
+
+// componentPanicked applies the panic policy to a component whose Start panicked
+func (m *Manager) componentPanicked(s namedComponent, generation int, err error) {
+	action := PanicSupervise
+	if s.options.onPanic != nil {
+		action = s.options.onPanic(err)
+	}
+
+	switch action {
+	case PanicAbort:
+		if m.isCurrent(generation) {
+			m.abort(newComponentError(s, ErrStartFailed, err))
+		}
+	case PanicRestart:
+		if m.isCurrent(generation) {
+			attempt := m.countRestart(panicRestartsKey(s))
+			go m.relaunch(s, m.restartDelay(attempt),
+				fmt.Sprintf("Restarting component %q after panic (attempt %d)", s.name, attempt), slog.String("component_name", s.name), slog.Int("attempt", attempt))
+		}
+	case PanicIgnore:
+	default:
+		m.componentFailed(s, generation, err)
+	}
+}
+
+// panicRestartsKey is the restart counter key of a component restarted after panics, apart from its supervised restarts
+func panicRestartsKey(s namedComponent) string {
+	return "panic:" + s.name
+}
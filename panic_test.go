@@ -0,0 +1,93 @@
+package unixcycle_test
+
+import (
+	"context"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestOnPanic(t *testing.T) {
+	t.Run("should restart a component with RestartOnPanic without supervision", func(t *testing.T) {
+		var (
+			starts = atomic.Int32{}
+			sut    = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).
+				Add("flaky worker", unixcycle.Starter(func() error {
+					if starts.Add(1) == 1 {
+						panic("boom")
+					}
+					select {}
+				}), unixcycle.OnPanic(unixcycle.RestartOnPanic))
+			handle = sut.RunAsync()
+		)
+
+		assert.Eventually(t, func() bool { return starts.Load() == 2 }, 3*time.Second, time.Millisecond)
+		handle.Signal(int(syscall.SIGTERM))
+		got := <-handle.Done()
+
+		assert.Equal(t, int(syscall.SIGTERM), got.Signal)
+	})
+
+	t.Run("should abort with AbortOnPanic even with supervision", func(t *testing.T) {
+		sut := unixcycle.NewManager(
+			unixcycle.WithLifetime(func() int { select {} }),
+			unixcycle.WithSupervision(unixcycle.OneForOne, unixcycle.ConstantBackoff(time.Millisecond), 3),
+		).
+			Add("critical", unixcycle.Starter(func() error { panic("boom") }), unixcycle.OnPanic(unixcycle.AbortOnPanic))
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, int(syscall.SIGABRT), got.Signal)
+		assert.ErrorIs(t, got.Err, unixcycle.ErrStartFailed)
+		assert.ErrorContains(t, got.Err, "panic: boom")
+	})
+
+	t.Run("should let a policy decide to ignore a panic and keep the other components running", func(t *testing.T) {
+		var (
+			decided = make(chan error, 1)
+			policy  = func(err error) unixcycle.PanicAction { decided <- err; return unixcycle.PanicIgnore }
+			sut     = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).
+				Add("optional", unixcycle.Starter(func() error { panic("boom") }), unixcycle.OnPanic(policy)).
+				Add("server", unixcycle.Starter(func() error { select {} }))
+			handle = sut.RunAsync()
+		)
+
+		assert.ErrorContains(t, <-decided, "boom")
+		report := sut.Health(context.Background())
+		assert.Equal(t, unixcycle.StateFailed, report.Components[0].State)
+		assert.Equal(t, unixcycle.StateRunning, report.Components[1].State)
+		handle.Signal(int(syscall.SIGTERM))
+		got := <-handle.Done()
+
+		assert.Equal(t, int(syscall.SIGTERM), got.Signal)
+		assert.NoError(t, got.Err)
+	})
+
+	t.Run("should pass the recovered value and the stack to the panic handler", func(t *testing.T) {
+		var (
+			component string
+			recovered any
+			stack     []byte
+			sut       = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithPanicHandler(func(c string, r any, s []byte) { component, recovered, stack = c, r, s }),
+			).
+				Add("worker", unixcycle.Starter(panickingStart))
+		)
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, int(syscall.SIGABRT), got.Signal)
+		assert.Equal(t, "worker", component)
+		assert.Equal(t, "boom", recovered)
+		assert.Contains(t, string(stack), "panickingStart", "should contain the frame that panicked")
+	})
+}
+
+func panickingStart() error {
+	panic("boom")
+}
@@ -0,0 +1,145 @@
+package unixcycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var _ Component = &poolComponent[any]{}
+
+// PoolStats reports what happened to the jobs handled by a pool
+type PoolStats struct {
+	Handled int
+	Failed  int
+}
+
+// poolComponent handles jobs from a channel with a fixed number of workers
+type poolComponent[T any] struct {
+	workers       int
+	jobs          <-chan T
+	handle        func(ctx context.Context, job T) error
+	drainDeadline time.Duration
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+	stats   PoolStats
+	stop    chan struct{}
+	done    chan struct{}
+	cancel  context.CancelFunc
+}
+
+// Pool creates a component handling the jobs received on jobs with the given number of concurrent workers.
+// Jobs failing to be handled are counted, but don't stop the pool, see Stats.
+// Start returns once jobs is closed and every job is handled. On Close the workers stop receiving jobs
+// and the jobs in progress get drainDeadline to finish, after which the context passed to handle is cancelled.
+// A pool closed before it started doesn't take any jobs
+func Pool[T any](workers int, jobs <-chan T, handle func(ctx context.Context, job T) error, drainDeadline time.Duration) *poolComponent[T] {
+	return &poolComponent[T]{
+		workers:       workers,
+		jobs:          jobs,
+		handle:        handle,
+		drainDeadline: drainDeadline,
+	}
+}
+
+// Setup allows the pool to be started again after it was closed, e.g. on a restart
+func (p *poolComponent[T]) Setup() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = false
+	return nil
+}
+
+func (p *poolComponent[T]) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		cancel()
+		return nil // Closed before it started
+	}
+	if p.started {
+		p.mu.Unlock()
+		cancel()
+		return fmt.Errorf("pool already started")
+	}
+	p.started = true
+	p.stop, p.done, p.cancel = make(chan struct{}), make(chan struct{}), cancel
+	stop, done := p.stop, p.done
+	p.mu.Unlock()
+	defer close(done)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for range p.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx, stop)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+func (p *poolComponent[T]) work(ctx context.Context, stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+			// Don't take another job once stopped, even if one is ready
+		}
+
+		select {
+		case <-stop:
+			return
+		case job, ok := <-p.jobs:
+			if !ok {
+				return
+			}
+
+			err := p.handle(ctx, job)
+			p.mu.Lock()
+			if err != nil {
+				p.stats.Failed++
+			} else {
+				p.stats.Handled++
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+// Close stops receiving jobs and waits for the jobs in progress, cancelling them after the drain deadline
+func (p *poolComponent[T]) Close() error {
+	p.mu.Lock()
+	started, stop, done, cancel := p.started, p.stop, p.done, p.cancel
+	p.started = false
+	p.closed = true // Until set up again, e.g. on a restart
+	p.mu.Unlock()
+	if !started {
+		return nil
+	}
+
+	close(stop)
+	select {
+	case <-done:
+		return nil
+	case <-time.After(p.drainDeadline):
+	}
+
+	cancel()
+	<-done
+	return fmt.Errorf("jobs in progress did not finish within %s and were cancelled", p.drainDeadline)
+}
+
+// Stats returns how many jobs have been handled and failed so far
+func (p *poolComponent[T]) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats
+}
@@ -0,0 +1,71 @@
+package unixcycle_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestPool(t *testing.T) {
+	t.Run("should handle all jobs concurrently until the channel is closed", func(t *testing.T) {
+		var (
+			jobs = make(chan int, 4)
+			sut  = unixcycle.Pool(2, jobs, func(ctx context.Context, job int) error {
+				if job%2 == 0 {
+					return assert.AnError
+				}
+				return nil
+			}, time.Second)
+		)
+		for job := range 4 {
+			jobs <- job
+		}
+		close(jobs)
+
+		require.NoError(t, sut.Start())
+
+		assert.Equal(t, unixcycle.PoolStats{Handled: 2, Failed: 2}, sut.Stats())
+	})
+
+	t.Run("should cancel jobs in progress after the drain deadline", func(t *testing.T) {
+		var (
+			jobs    = make(chan int, 1)
+			started = make(chan struct{})
+			sut     = unixcycle.Pool(1, jobs, func(ctx context.Context, job int) error {
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			}, 10*time.Millisecond)
+			result = make(chan error, 1)
+		)
+		jobs <- 1
+		go func() { result <- sut.Start() }()
+		<-started
+
+		err := sut.Close()
+
+		assert.ErrorContains(t, err, "did not finish within 10ms")
+		assert.NoError(t, <-result)
+		assert.Equal(t, unixcycle.PoolStats{Failed: 1}, sut.Stats())
+	})
+
+	t.Run("should start again after being set up", func(t *testing.T) {
+		var (
+			jobs = make(chan int, 1)
+			sut  = unixcycle.Pool(1, jobs, func(ctx context.Context, job int) error {
+				return nil
+			}, time.Second)
+		)
+		require.NoError(t, sut.Close())
+		require.NoError(t, sut.Setup())
+		jobs <- 1
+		close(jobs)
+
+		assert.NoError(t, sut.Start())
+		assert.Equal(t, unixcycle.PoolStats{Handled: 1}, sut.Stats())
+	})
+}
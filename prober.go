@@ -0,0 +1,203 @@
+package unixcycle
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"time"
+)
+
+// maxProbeBody caps how much of a response body HTTPProber reads, for the body matcher and errors
+const maxProbeBody = 64 << 10
+
+type httpProberOptions struct {
+	client         *http.Client
+	statusCodes    []int
+	attemptTimeout time.Duration
+	matchBody      func(body []byte) bool
+}
+
+type httpProberOption func(*httpProberOptions)
+
+// WithExpectedStatus makes HTTPProber succeed only on the given status codes. Default is any 2xx status
+func WithExpectedStatus(codes ...int) httpProberOption {
+	return func(o *httpProberOptions) {
+		o.statusCodes = codes
+	}
+}
+
+// WithAttemptTimeout bounds every request of HTTPProber, e.g. when retried by RetryingProber.
+// Default is no timeout besides the one of the context
+func WithAttemptTimeout(timeout time.Duration) httpProberOption {
+	return func(o *httpProberOptions) {
+		o.attemptTimeout = timeout
+	}
+}
+
+// WithBodyMatcher makes HTTPProber also require the response body to match, e.g. a health endpoint reporting "ok".
+// Only the first 64KiB of the body are matched
+func WithBodyMatcher(match func(body []byte) bool) httpProberOption {
+	return func(o *httpProberOptions) {
+		o.matchBody = match
+	}
+}
+
+// WithProbeClient sets the client HTTPProber sends its requests with, e.g. for TLS. Default is http.DefaultClient
+func WithProbeClient(client *http.Client) httpProberOption {
+	return func(o *httpProberOptions) {
+		o.client = client
+	}
+}
+
+// HTTPProber sends a GET request to url and succeeds on a 2xx status, see WithExpectedStatus and WithBodyMatcher.
+// It makes a single attempt, wrap it in RetryingProber to wait for a service to come up:
+//
+//	unixcycle.RetryingProber(100*time.Millisecond, 10*time.Second, unixcycle.HTTPProber("http://localhost:8080/healthz"))
+func HTTPProber(url string, options ...httpProberOption) ProberFunc {
+	o := &httpProberOptions{client: http.DefaultClient}
+	for _, option := range options {
+		option(o)
+	}
+
+	return func(ctx context.Context) error {
+		if o.attemptTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, o.attemptTimeout)
+			defer cancel()
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return fmt.Errorf("creating probe request: %w", err)
+		}
+		resp, err := o.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("probing %s: %w", url, err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeBody))
+		if err != nil {
+			return fmt.Errorf("reading probe response of %s: %w", url, err)
+		}
+		if !o.expectedStatus(resp.StatusCode) {
+			return fmt.Errorf("probing %s: unexpected status %s: %s", url, resp.Status, truncate(body))
+		}
+		if o.matchBody != nil && !o.matchBody(body) {
+			return fmt.Errorf("probing %s: body did not match: %s", url, truncate(body))
+		}
+		return nil
+	}
+}
+
+// CommandProber runs the command name with args and succeeds when it exits with 0, e.g. for vendor CLIs like
+// pg_isready or redis-cli ping. On failure the error holds the output of the command.
+// The command is killed when the context is done, wrap it in RetryingProber to wait for a service to come up
+func CommandProber(name string, args ...string) ProberFunc {
+	return func(ctx context.Context) error {
+		var (
+			cmd            = exec.CommandContext(ctx, name, args...)
+			stdout, stderr bytes.Buffer
+		)
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		cmd.WaitDelay = time.Second // Don't wait for children holding on to the output after the command was killed
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("probing with %s: %w (stdout: %q, stderr: %q)", name, err, truncate(stdout.Bytes()), truncate(stderr.Bytes()))
+		}
+		return nil
+	}
+}
+
+// SQLProber pings the database of db, e.g. to wait for a database in docker compose:
+//
+//	unixcycle.RetryingProber(100*time.Millisecond, 30*time.Second, unixcycle.SQLProber(db))
+func SQLProber(db *sql.DB) ProberFunc {
+	return func(ctx context.Context) error {
+		if err := db.PingContext(ctx); err != nil {
+			return fmt.Errorf("pinging database: %w", err)
+		}
+		return nil
+	}
+}
+
+// SQLDSNProber opens the database at dsn with the registered driver, pings it and closes it again on every attempt,
+// for when there is no pool to probe yet
+func SQLDSNProber(driverName, dsn string) ProberFunc {
+	return func(ctx context.Context) error {
+		db, err := sql.Open(driverName, dsn)
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer db.Close()
+
+		return SQLProber(db)(ctx)
+	}
+}
+
+type fileProberOptions struct {
+	nonEmpty bool
+	match    func(path string, info fs.FileInfo) bool
+}
+
+type fileProberOption func(*fileProberOptions)
+
+// WithNonEmptyFile makes FileProber wait for the file to have content, e.g. a pid or port written to it
+func WithNonEmptyFile() fileProberOption {
+	return func(o *fileProberOptions) {
+		o.nonEmpty = true
+	}
+}
+
+// WithFilePredicate makes FileProber wait for match to accept the file, e.g. reading it for a "ready" marker
+func WithFilePredicate(match func(path string, info fs.FileInfo) bool) fileProberOption {
+	return func(o *fileProberOptions) {
+		o.match = match
+	}
+}
+
+// FileProber succeeds once path exists, for components that signal their readiness by touching a file or
+// creating a unix socket
+func FileProber(path string, options ...fileProberOption) ProberFunc {
+	o := &fileProberOptions{}
+	for _, option := range options {
+		option(o)
+	}
+
+	return func(ctx context.Context) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("probing %s: %w", path, err)
+		}
+		if o.nonEmpty && info.Size() == 0 {
+			return fmt.Errorf("probing %s: file is empty", path)
+		}
+		if o.match != nil && !o.match(path, info) {
+			return fmt.Errorf("probing %s: file did not match", path)
+		}
+		return nil
+	}
+}
+
+func (o *httpProberOptions) expectedStatus(code int) bool {
+	if len(o.statusCodes) == 0 {
+		return code >= 200 && code < 300
+	}
+	return slices.Contains(o.statusCodes, code)
+}
+
+// truncate shortens the output of a probe for an error message
+func truncate(output []byte) string {
+	const maxLen = 256
+	if len(output) > maxLen {
+		return string(output[:maxLen]) + "..."
+	}
+	return string(output)
+}
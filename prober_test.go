@@ -0,0 +1,304 @@
+package unixcycle_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestHTTPProber(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T, status int, body string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	t.Run("should succeed on a 2xx status by default", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		var (
+			server = newServer(t, http.StatusNoContent, "")
+			sut    = unixcycle.HTTPProber(server.URL)
+		)
+
+		// Act
+		err := sut(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should fail on an unexpected status with the body in the error", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		var (
+			server = newServer(t, http.StatusServiceUnavailable, "database unreachable")
+			sut    = unixcycle.HTTPProber(server.URL)
+		)
+
+		// Act
+		err := sut(context.Background())
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "unexpected status 503 Service Unavailable")
+		assert.ErrorContains(t, err, "database unreachable")
+	})
+
+	t.Run("should succeed only on the expected status codes", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		var (
+			server   = newServer(t, http.StatusUnauthorized, "")
+			sut      = unixcycle.HTTPProber(server.URL, unixcycle.WithExpectedStatus(http.StatusUnauthorized))
+			okServer = newServer(t, http.StatusOK, "")
+			okSut    = unixcycle.HTTPProber(okServer.URL, unixcycle.WithExpectedStatus(http.StatusUnauthorized))
+		)
+
+		// Act
+		err := sut(context.Background())
+		okErr := okSut(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.ErrorContains(t, okErr, "unexpected status 200 OK")
+	})
+
+	t.Run("should require the body to match", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		var (
+			server  = newServer(t, http.StatusOK, "status: starting")
+			matcher = func(body []byte) bool { return bytes.Contains(body, []byte("ok")) }
+			sut     = unixcycle.HTTPProber(server.URL, unixcycle.WithBodyMatcher(matcher))
+		)
+
+		// Act
+		err := sut(context.Background())
+
+		// Assert
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "body did not match: status: starting")
+	})
+
+	t.Run("should time out a single attempt", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		var (
+			release = make(chan struct{})
+			server  = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			}))
+			sut = unixcycle.HTTPProber(server.URL, unixcycle.WithAttemptTimeout(50*time.Millisecond))
+		)
+		defer server.Close()
+		defer close(release)
+
+		// Act
+		began := time.Now()
+		err := sut(context.Background())
+
+		// Assert
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(began), time.Second)
+	})
+
+	t.Run("should retry until the service is up when wrapped in a RetryingProber", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		var (
+			requests int
+			server   = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests < 3 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			sut = unixcycle.RetryingProber(10*time.Millisecond, time.Second, unixcycle.HTTPProber(server.URL))
+		)
+		defer server.Close()
+
+		// Act
+		err := sut(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 3, requests)
+	})
+}
+
+func TestSQLProber(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should ping the database", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		d, open := openFake(t)
+		db, err := open()
+		require.NoError(t, err)
+		defer db.Close()
+		sut := unixcycle.SQLProber(db)
+
+		// Act
+		err = sut(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, d.pings.Load())
+	})
+
+	t.Run("should fail while the database can't be reached", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		d, open := openFake(t)
+		db, err := open()
+		require.NoError(t, err)
+		defer db.Close()
+		d.down.Store(true)
+		sut := unixcycle.SQLProber(db)
+
+		// Act
+		err = sut(context.Background())
+
+		// Assert
+		assert.ErrorIs(t, err, driver.ErrBadConn)
+		assert.ErrorContains(t, err, "pinging database")
+	})
+
+	t.Run("should open and ping the database of a dsn", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		var (
+			d, name = registerFake(t)
+			sut     = unixcycle.SQLDSNProber(name, "postgres://localhost/orders")
+		)
+		d.down.Store(true)
+
+		// Act
+		downErr := sut(context.Background())
+		d.down.Store(false)
+		upErr := sut(context.Background())
+
+		// Assert
+		assert.ErrorIs(t, downErr, driver.ErrBadConn)
+		assert.NoError(t, upErr)
+	})
+
+	t.Run("should fail on an unknown driver", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		sut := unixcycle.SQLDSNProber("does-not-exist-unixcycle", "")
+
+		// Act
+		err := sut(context.Background())
+
+		// Assert
+		assert.ErrorContains(t, err, "opening database")
+	})
+}
+
+func TestFileProber(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should fail until the file exists", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		var (
+			path = filepath.Join(t.TempDir(), "ready")
+			sut  = unixcycle.FileProber(path)
+		)
+
+		// Act
+		missingErr := sut(context.Background())
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+		err := sut(context.Background())
+
+		// Assert
+		assert.ErrorIs(t, missingErr, fs.ErrNotExist)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should succeed once a unix socket is created", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		dir, err := os.MkdirTemp("", "uc") // Short, socket paths are limited to about 100 bytes
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		var (
+			path = filepath.Join(dir, "admin.sock")
+			sut  = unixcycle.FileProber(path)
+		)
+		listener, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		defer listener.Close()
+
+		// Act
+		err = sut(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should wait for the file to have content", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		var (
+			path = filepath.Join(t.TempDir(), "port")
+			sut  = unixcycle.FileProber(path, unixcycle.WithNonEmptyFile())
+		)
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+		// Act
+		emptyErr := sut(context.Background())
+		require.NoError(t, os.WriteFile(path, []byte("8080"), 0o600))
+		err := sut(context.Background())
+
+		// Assert
+		assert.ErrorContains(t, emptyErr, "file is empty")
+		assert.NoError(t, err)
+	})
+
+	t.Run("should wait for the predicate to accept the file", func(t *testing.T) {
+		t.Parallel()
+		// Arrange
+		var (
+			path      = filepath.Join(t.TempDir(), "status")
+			predicate = func(path string, info fs.FileInfo) bool {
+				content, err := os.ReadFile(path)
+				return err == nil && bytes.Equal(content, []byte("ready"))
+			}
+			sut = unixcycle.FileProber(path, unixcycle.WithFilePredicate(predicate))
+		)
+		require.NoError(t, os.WriteFile(path, []byte("starting"), 0o600))
+
+		// Act
+		startingErr := sut(context.Background())
+		require.NoError(t, os.WriteFile(path, []byte("ready"), 0o600))
+		err := sut(context.Background())
+
+		// Assert
+		assert.ErrorContains(t, startingErr, "file did not match")
+		assert.NoError(t, err)
+	})
+}
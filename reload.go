@@ -0,0 +1,85 @@
+package unixcycle
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"syscall"
+)
+
+// reloadable is an optional interface for components that can reload their configuration without a restart, see Manager.Reload
+type reloadable interface {
+	Reload() error
+}
+
+// WithReloadSignals sets the signals that make the manager reload its components instead of shutting down
+// Passing no signals disables reloading on signals, Manager.Reload can still be called
+// Default is SIGHUP, only listened to when a component implements Reload() error
+func WithReloadSignals(signals ...os.Signal) managerOption {
+	return func(o *managerOptions) {
+		o.reloadSignals = append([]os.Signal{}, signals...) // Not nil, so no signals disables the default
+	}
+}
+
+// Reload calls Reload on every running component implementing Reload() error, in the order they are set up.
+// Every reload is bounded by the setup timeout of the component. A failing reload does not stop the others,
+// nor the component itself: it keeps running with its previous configuration
+func (m *Manager) Reload() error {
+	m.lifecycle.Lock() // Don't reload components that are being restarted or closed
+	defer m.lifecycle.Unlock()
+
+	return m.reloadComponents(func(c Component) func() error {
+		if reloadable, ok := c.(reloadable); ok {
+			return reloadable.Reload
+		}
+		return nil
+	})
+}
+
+// reloadComponents calls the function reloadFunc returns for every running component, skipping those it returns nil for.
+// The caller holds the lifecycle lock
+func (m *Manager) reloadComponents(reloadFunc func(c Component) func() error) error {
+	m.mu.Lock()
+	components := slices.Clone(m.components)
+	m.mu.Unlock()
+
+	var errs []error
+	for _, s := range components {
+		reload := reloadFunc(s.Component)
+		if state := m.state(s); reload == nil || (state != StateRunning && state != StateStopped) {
+			continue
+		}
+
+		m.logInfo(CodeReloadBegin, fmt.Sprintf("Reloading component %q", s.name), slog.String("component_name", s.name))
+		err := funcOrTimeout(reload, cmp.Or(s.options.setupTimeout, m.setupTimeout))
+		if err != nil {
+			m.trackTimedOut(s, "Reload", err)
+			m.logError(CodeReloadFailed, fmt.Sprintf("Failure during reload for component %q: %v", s.name, err), slog.String("component_name", s.name))
+			errs = append(errs, newComponentError(s, ErrReloadFailed, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// reloadOnSignals reloads the components every time one of the reload signals is received, until stop is called
+func (m *Manager) reloadOnSignals() (stop func()) {
+	reloadSignals := m.reloadSignals
+	if reloadSignals == nil {
+		reloadSignals = []os.Signal{syscall.SIGHUP}
+	}
+	if !slices.ContainsFunc(m.components, func(s namedComponent) bool {
+		_, ok := s.Component.(reloadable)
+		return ok
+	}) {
+		return func() {}
+	}
+
+	return m.handleSignals(reloadSignals, func(sig os.Signal) {
+		m.logInfo(CodeSignalReceived, fmt.Sprintf("Received reload signal: %v", sig), slog.String("signal", sig.String()))
+		_ = m.Reload() // Failures are logged per component
+	})
+}
@@ -0,0 +1,52 @@
+package unixcycle_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestReload(t *testing.T) {
+	t.Run("should reload running components and report failures", func(t *testing.T) {
+		var (
+			good = &reloadComponent{}
+			bad  = &reloadComponent{err: assert.AnError}
+			sut  = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).
+				Add("good", good).
+				Add("bad", bad)
+		)
+		assert.NoError(t, sut.Reload(), "nothing is running yet")
+		handle := sut.RunAsync()
+		defer func() {
+			handle.Signal(int(syscall.SIGTERM))
+			<-handle.Done()
+		}()
+		require.Eventually(t, func() bool {
+			return unixcycle.ManagerReadyProber(sut)(context.Background()) == nil
+		}, time.Second, time.Millisecond)
+
+		err := sut.Reload()
+
+		assert.ErrorIs(t, err, unixcycle.ErrReloadFailed)
+		assert.ErrorContains(t, err, `component "bad"`)
+		assert.Equal(t, 1, good.reloads)
+		assert.Equal(t, 1, bad.reloads)
+	})
+}
+
+type reloadComponent struct {
+	reloads int
+	err     error
+}
+
+func (c *reloadComponent) Start() error { return nil }
+
+func (c *reloadComponent) Reload() error {
+	c.reloads++
+	return c.err
+}
@@ -0,0 +1,169 @@
+package unixcycle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// componentLabel is the pprof label attached to the start goroutine of each component.
+// Goroutines spawned from Start inherit it, which lets us attribute them to the component
+const componentLabel = "unixcycle_component"
+
+// ResourceUsage is a snapshot of the resources used by the process, attributed per component where feasible
+type ResourceUsage struct {
+	// Goroutines is the number of live goroutines started by (or on behalf of) each component
+	Goroutines map[string]int
+	// TotalGoroutines is the number of goroutines in the whole process
+	TotalGoroutines int
+	// HeapAlloc is the number of bytes of allocated heap objects in the whole process
+	HeapAlloc uint64
+	// TimedOut is the number of operations of components (Setup, Ready, Reload, Drain, Checkpoint, Close)
+	// that timed out but are still running
+	TimedOut int
+}
+
+// ResourceUsage takes a snapshot of the goroutines per component and the memory of the process.
+// Goroutines are only attributed to a component if they were started from within its Start method
+func (m *Manager) ResourceUsage() ResourceUsage {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	perLabel := goroutinesPerLabel(componentLabel)
+	goroutines := make(map[string]int, len(m.components))
+	for _, s := range m.components {
+		if _, ok := s.Component.(startable); ok {
+			goroutines[s.name] = perLabel[s.name]
+		}
+	}
+
+	return ResourceUsage{
+		Goroutines:      goroutines,
+		TotalGoroutines: runtime.NumGoroutine(),
+		HeapAlloc:       mem.HeapAlloc,
+		TimedOut:        int(m.timedOut.Load()),
+	}
+}
+
+// resourceUsageVar is the name the last reported ResourceUsage is published under with expvar,
+// so it is served under /debug/vars, e.g. by DebugServer
+const resourceUsageVar = "unixcycle_resource_usage"
+
+var (
+	lastResourceUsage    atomic.Pointer[ResourceUsage]
+	publishResourceUsage sync.Once // expvar panics when a name is published twice, e.g. by a second manager
+)
+
+// reportResourceUsage logs, publishes and emits a resource usage snapshot every interval until ctx is done
+func (m *Manager) reportResourceUsage(ctx context.Context, interval time.Duration) {
+	publishResourceUsage.Do(func() {
+		expvar.Publish(resourceUsageVar, expvar.Func(func() any { return lastResourceUsage.Load() }))
+	})
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			usage := m.ResourceUsage()
+			attrs := []any{slog.Int("goroutines", usage.TotalGoroutines), slog.Uint64("heap_alloc", usage.HeapAlloc)}
+			for name, count := range usage.Goroutines {
+				attrs = append(attrs, slog.Int("goroutines_"+name, count))
+			}
+			m.logInfo(CodeResourceUsage, fmt.Sprintf("Resource usage: %d goroutines, %d bytes heap", usage.TotalGoroutines, usage.HeapAlloc), attrs...)
+			lastResourceUsage.Store(&usage)
+			m.emitEvent(Event{Kind: EventResourceUsage, Time: time.Now(), Code: CodeResourceUsage, Usage: &usage})
+		}
+	}
+}
+
+// phaseLabel is the pprof label telling which lifecycle phase a component goroutine was started for
+const phaseLabel = "unixcycle_phase"
+
+// withComponentLabel runs f with the component and phase labels set, so every goroutine started from f is attributed to name
+func withComponentLabel(name string, phase string, f func()) {
+	pprof.Do(context.Background(), pprof.Labels(componentLabel, name, phaseLabel, phase), func(context.Context) { f() })
+}
+
+// goroutineRecord is a group of goroutines sharing the same stack and labels
+type goroutineRecord struct {
+	count  int
+	labels map[string]string
+	stack  []string
+}
+
+// goroutineRecords parses the debug=1 text format of the goroutine profile, where every stack is written as
+// "<count> @ <pcs>" optionally followed by a "# labels: {...}" line and a "#\t<pc>\t<func>\t<file:line>" line per frame
+func goroutineRecords() []goroutineRecord {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return nil
+	}
+
+	var (
+		records []goroutineRecord
+		scanner = bufio.NewScanner(&buf)
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if n, _, ok := strings.Cut(line, " @ "); ok {
+			count, _ := strconv.Atoi(n)
+			records = append(records, goroutineRecord{count: count, labels: map[string]string{}})
+			continue
+		}
+		if len(records) == 0 {
+			continue // Header
+		}
+		record := &records[len(records)-1]
+		if rawLabels, ok := strings.CutPrefix(line, "# labels: "); ok {
+			_ = json.Unmarshal([]byte(rawLabels), &record.labels) // Unlabeled on the off chance the format changes
+			continue
+		}
+		if frame, ok := strings.CutPrefix(line, "#\t"); ok {
+			if _, frame, ok = strings.Cut(frame, "\t"); ok { // Drop the pc
+				record.stack = append(record.stack, strings.TrimSpace(frame))
+			}
+		}
+	}
+
+	return records
+}
+
+// goroutinesPerLabel counts goroutines by the value of the given pprof label
+func goroutinesPerLabel(key string) map[string]int {
+	counts := map[string]int{}
+	for _, record := range goroutineRecords() {
+		if value, ok := record.labels[key]; ok {
+			counts[value] += record.count
+		}
+	}
+
+	return counts
+}
+
+// goroutineStacks returns the stacks of all goroutines carrying the given component and phase labels
+func goroutineStacks(name string, phase string) []string {
+	var stacks []string
+	for _, record := range goroutineRecords() {
+		if record.labels[componentLabel] == name && record.labels[phaseLabel] == phase {
+			stacks = append(stacks, strings.Join(record.stack, "\n"))
+		}
+	}
+
+	return stacks
+}
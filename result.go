@@ -0,0 +1,83 @@
+package unixcycle
+
+import (
+	"errors"
+	"syscall"
+	"time"
+)
+
+// Phase is a phase of the lifecycle of the manager
+type Phase string
+
+const (
+	PhaseAdd          Phase = "add"
+	PhaseDependencies Phase = "dependencies"
+	PhaseRestoreState Phase = "restore state"
+	PhaseSetup        Phase = "setup"
+	PhaseStart        Phase = "start"
+	PhaseDrain        Phase = "drain"
+	PhaseCheckpoint   Phase = "checkpoint"
+	PhaseSaveState    Phase = "save state"
+	PhaseClose        Phase = "close"
+)
+
+// Result describes how a run of the manager ended: the exit signal, the exit code, the error and the failed phase.
+// RunWithResult, Wait, WaitContext and RunHandle.Done return it, Run and RunContext return its ExitCode and Start its Err
+type Result struct {
+	// Signal is the exit signal on a graceful shutdown, SIGALRM when the manager aborted on a timeout and SIGABRT on any other failure
+	Signal int
+	// ExitCode is the value returned from Run: Signal, unless mapped by WithExitCodeMapper
+	ExitCode int
+	// Err is the error that made the manager abort, nil on a graceful shutdown
+	Err error
+	// FailedPhase is the first phase that failed, empty on a graceful shutdown
+	FailedPhase Phase
+	// ComponentErrors holds the last error of every component that failed during the run, keyed by component name
+	ComponentErrors map[string]error
+
+	SetupDuration    time.Duration
+	RunDuration      time.Duration // From the components being started until the exit signal
+	ShutdownDuration time.Duration
+}
+
+// newResult maps err to the signal returned from Run, falling back to signal when err is nil
+func newResult(signal int, err error) Result {
+	switch {
+	case errors.Is(err, errTimeout):
+		return Result{Signal: int(syscall.SIGALRM), Err: err}
+	case err != nil:
+		return Result{Signal: int(syscall.SIGABRT), Err: err}
+	default:
+		return Result{Signal: signal}
+	}
+}
+
+// failedResult is the result of the manager aborting in phase
+func failedResult(phase Phase, err error) Result {
+	result := newResult(0, err)
+	result.FailedPhase = phase
+	return result
+}
+
+// WithExitCodeMapper sets the value returned from Run, e.g. to give every failure class its own exit code for ops tooling:
+//
+//	unixcycle.WithExitCodeMapper(func(result unixcycle.Result) int {
+//		switch {
+//		case result.Err == nil:
+//			return 0
+//		case errors.Is(result.Err, unixcycle.ErrSetupTimeout):
+//			return 3
+//		case result.FailedPhase == unixcycle.PhaseClose:
+//			return 4
+//		default:
+//			return 1
+//		}
+//	})
+//
+// The mapper runs before the hooks registered with WithAfterShutdown, which see the mapped Result.ExitCode
+// Default is returning Result.Signal
+func WithExitCodeMapper(mapper func(Result) int) managerOption {
+	return func(o *managerOptions) {
+		o.exitCodeMapper = mapper
+	}
+}
@@ -0,0 +1,303 @@
+package unixcycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schedule decides when a scheduled job runs next, see Scheduled
+type Schedule interface {
+	// Next returns the first time after t the job should run, the zero time if it should never run again
+	Next(t time.Time) time.Time
+}
+
+// ScheduleFunc adapts a function to a Schedule
+type ScheduleFunc func(t time.Time) time.Time
+
+func (f ScheduleFunc) Next(t time.Time) time.Time {
+	return f(t)
+}
+
+// Interval is a Schedule running a job every interval, the first time one interval after starting
+func Interval(interval time.Duration) Schedule {
+	return ScheduleFunc(func(t time.Time) time.Time { return t.Add(interval) })
+}
+
+// ErrorPolicy decides what a scheduled job does with the error of a run:
+// returning nil keeps the schedule going, returning an error fails the component with it
+type ErrorPolicy func(err error) error
+
+// FailOnError fails the component on the first error of a run. It is the default policy
+func FailOnError(err error) error {
+	return err
+}
+
+// LogErrors logs the errors of runs to logger and keeps the schedule going. A nil logger logs to the logger
+// the manager hands to the component, tagged with the component name, see SetLogger
+func LogErrors(logger *slog.Logger) ErrorPolicy {
+	return func(err error) error {
+		if logger == nil {
+			return &componentLoggedError{err: err}
+		}
+		logger.Error("Scheduled job failed", slog.String("error", err.Error()))
+		return nil
+	}
+}
+
+// componentLoggedError asks the scheduled component to log err to its own logger and keep going, see LogErrors
+type componentLoggedError struct {
+	err error
+}
+
+func (e *componentLoggedError) Error() string {
+	return e.err.Error()
+}
+
+var _ Component = &scheduledComponent{}
+
+// scheduledComponent runs a job on a schedule as its Start loop
+type scheduledComponent struct {
+	schedule    Schedule
+	job         func(ctx context.Context) error
+	errorPolicy ErrorPolicy
+
+	mu      sync.Mutex
+	logger  *slog.Logger
+	started bool
+	closed  bool
+	stop    chan struct{}
+	done    chan struct{}
+	cancel  context.CancelFunc
+}
+
+// Scheduled creates a component running job on schedule, e.g. Interval(time.Minute) or a Cron schedule.
+// Runs never overlap: when a run takes longer than the schedule, the runs that were missed are skipped.
+// A job returning an error stops the schedule and fails the component, unless changed with WithErrorPolicy.
+// On Close no more runs are started, and the context of a run in progress is cancelled and waited for.
+// A schedule closed before it started doesn't run at all
+func Scheduled(schedule Schedule, job func(ctx context.Context) error) *scheduledComponent {
+	return &scheduledComponent{
+		schedule:    schedule,
+		job:         job,
+		errorPolicy: FailOnError,
+		logger:      slog.Default(),
+	}
+}
+
+// Every creates a component running job every interval, see Scheduled
+func Every(interval time.Duration, job func(ctx context.Context) error) *scheduledComponent {
+	return Scheduled(Interval(interval), job)
+}
+
+// WithErrorPolicy sets what happens when a run fails, e.g. LogErrors to keep going. Default is FailOnError
+func (s *scheduledComponent) WithErrorPolicy(policy ErrorPolicy) *scheduledComponent {
+	s.errorPolicy = policy
+	return s
+}
+
+// SetLogger is called by the manager with its logger, tagged with the component name, see LogErrors
+func (s *scheduledComponent) SetLogger(logger *slog.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger = logger
+}
+
+// Setup allows the schedule to be started again after it was closed, e.g. on a restart
+func (s *scheduledComponent) Setup() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = false
+	return nil
+}
+
+func (s *scheduledComponent) Start() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil // Closed before it started
+	}
+	if s.started {
+		s.mu.Unlock()
+		return fmt.Errorf("scheduled job already started")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.started = true
+	s.stop, s.done, s.cancel = make(chan struct{}), make(chan struct{}), cancel
+	stop, done, logger := s.stop, s.done, s.logger
+	s.mu.Unlock()
+	defer close(done)
+	defer cancel()
+
+	next := s.schedule.Next(time.Now())
+	for !next.IsZero() {
+		t := time.NewTimer(time.Until(next))
+		select {
+		case <-stop:
+			t.Stop()
+			return nil
+		case <-t.C:
+		}
+
+		if err := s.job(ctx); err != nil {
+			if err := s.errorPolicy(err); err != nil {
+				var logged *componentLoggedError
+				if !errors.As(err, &logged) {
+					return err
+				}
+				logger.Error("Scheduled job failed", slog.String("error", logged.err.Error()))
+			}
+		}
+		next = s.schedule.Next(time.Now())
+	}
+
+	<-stop // Never runs again, but keeps running until closed
+	return nil
+}
+
+// Close stops scheduling runs, cancels a run in progress and waits for it to finish
+func (s *scheduledComponent) Close() error {
+	s.mu.Lock()
+	started, stop, done, cancel := s.started, s.stop, s.done, s.cancel
+	s.started = false
+	s.closed = true // Until set up again, e.g. on a restart
+	s.mu.Unlock()
+	if !started {
+		return nil
+	}
+
+	close(stop)
+	cancel()
+	<-done
+	return nil
+}
+
+// cronSchedule is a standard 5 field cron expression: minute, hour, day of month, month and day of week
+type cronSchedule struct {
+	minutes, hours, days, months, weekdays uint64 // Bit sets of the matching values
+	daysRestricted, weekdaysRestricted     bool
+	location                               *time.Location
+}
+
+// Cron parses a standard 5 field cron expression ("minute hour day-of-month month day-of-week") into a Schedule
+// in the local time zone. Fields support "*", single values, ranges "1-5", lists "1,15" and steps "*/10" or "0-30/5".
+// Like in cron, a job runs when either the day of month or the day of week matches, if both are restricted
+func Cron(expression string) (Schedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", expression, len(fields))
+	}
+
+	var (
+		schedule = &cronSchedule{location: time.Local}
+		err      error
+	)
+	for i, field := range []struct {
+		bits     *uint64
+		min, max int
+	}{
+		{&schedule.minutes, 0, 59},
+		{&schedule.hours, 0, 23},
+		{&schedule.days, 1, 31},
+		{&schedule.months, 1, 12},
+		{&schedule.weekdays, 0, 7},
+	} {
+		*field.bits, err = parseCronField(fields[i], field.min, field.max)
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expression, err)
+		}
+	}
+	if schedule.weekdays&(1<<7) != 0 {
+		schedule.weekdays |= 1 // 7 is Sunday as well
+	}
+	schedule.daysRestricted = !strings.HasPrefix(fields[2], "*")
+	schedule.weekdaysRestricted = !strings.HasPrefix(fields[4], "*")
+
+	return schedule, nil
+}
+
+// MustCron is like Cron, but panics on an invalid expression
+func MustCron(expression string) Schedule {
+	schedule, err := Cron(expression)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		from, to := min, max
+		if rangePart != "*" {
+			fromPart, toPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if from, err = strconv.Atoi(fromPart); err != nil {
+				return 0, fmt.Errorf("invalid value in %q", part)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(toPart); err != nil {
+					return 0, fmt.Errorf("invalid range in %q", part)
+				}
+			} else if hasStep {
+				to = max // "5/10" means from 5 to the end in steps of 10
+			}
+		}
+		if from < min || to > max || from > to {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+
+		for v := from; v <= to; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (c *cronSchedule) Next(t time.Time) time.Time {
+	t = t.In(c.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0) // Expressions like "0 0 30 2 *" never match
+
+	for t.Before(limit) {
+		switch {
+		case c.months&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.location)
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.location)
+		case c.hours&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.location)
+		case c.minutes&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cronSchedule) matchesDay(t time.Time) bool {
+	var (
+		day     = c.days&(1<<t.Day()) != 0
+		weekday = c.weekdays&(1<<int(t.Weekday())) != 0
+	)
+	if c.daysRestricted && c.weekdaysRestricted {
+		return day || weekday
+	}
+	return day && weekday
+}
@@ -0,0 +1,176 @@
+package unixcycle_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestCron(t *testing.T) {
+	t.Run("should compute the next run of cron expressions", func(t *testing.T) {
+		from := time.Date(2024, time.January, 31, 10, 17, 30, 0, time.Local) // A Wednesday
+
+		for expression, want := range map[string]time.Time{
+			"* * * * *":      time.Date(2024, time.January, 31, 10, 18, 0, 0, time.Local),
+			"*/15 * * * *":   time.Date(2024, time.January, 31, 10, 30, 0, 0, time.Local),
+			"0 9-17 * * *":   time.Date(2024, time.January, 31, 11, 0, 0, 0, time.Local),
+			"30 2 1,15 * *":  time.Date(2024, time.February, 1, 2, 30, 0, 0, time.Local),
+			"0 0 29 2 *":     time.Date(2024, time.February, 29, 0, 0, 0, 0, time.Local),
+			"0 8 * * 1-5":    time.Date(2024, time.February, 1, 8, 0, 0, 0, time.Local),
+			"0 8 * * 7":      time.Date(2024, time.February, 4, 8, 0, 0, 0, time.Local),
+			"0 0 13 * 5":     time.Date(2024, time.February, 2, 0, 0, 0, 0, time.Local),
+			"0 0 30 2 *":     {},
+			"5/20 10 31 1 *": time.Date(2024, time.January, 31, 10, 25, 0, 0, time.Local),
+		} {
+			schedule, err := unixcycle.Cron(expression)
+			require.NoError(t, err, expression)
+
+			assert.Equal(t, want, schedule.Next(from), expression)
+		}
+	})
+
+	t.Run("should reject invalid cron expressions", func(t *testing.T) {
+		for _, expression := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+			_, err := unixcycle.Cron(expression)
+
+			assert.Error(t, err, expression)
+		}
+	})
+}
+
+func TestScheduled(t *testing.T) {
+	t.Run("should run the job on the schedule until closed", func(t *testing.T) {
+		var (
+			runs atomic.Int32
+			sut  = unixcycle.Scheduled(unixcycle.Interval(time.Millisecond), func(ctx context.Context) error {
+				runs.Add(1)
+				return nil
+			})
+			result = make(chan error, 1)
+		)
+		go func() { result <- sut.Start() }()
+		require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond)
+
+		require.NoError(t, sut.Close())
+		stopped := runs.Load()
+		time.Sleep(5 * time.Millisecond)
+
+		assert.NoError(t, <-result)
+		assert.Equal(t, stopped, runs.Load())
+	})
+
+	t.Run("should fail when the job fails", func(t *testing.T) {
+		sut := unixcycle.Scheduled(unixcycle.Interval(time.Millisecond), func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		assert.ErrorIs(t, sut.Start(), assert.AnError)
+	})
+
+	t.Run("should keep running when the error policy ignores the error", func(t *testing.T) {
+		var (
+			runs atomic.Int32
+			logs syncWriter
+			sut  = unixcycle.Every(time.Millisecond, func(ctx context.Context) error {
+				runs.Add(1)
+				return assert.AnError
+			}).WithErrorPolicy(unixcycle.LogErrors(slog.New(slog.NewTextHandler(&logs, nil))))
+			result = make(chan error, 1)
+		)
+		go func() { result <- sut.Start() }()
+		require.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond)
+
+		require.NoError(t, sut.Close())
+
+		assert.NoError(t, <-result)
+		assert.Contains(t, logs.String(), assert.AnError.Error())
+		assert.NotContains(t, logs.String(), "[UnixCycle]")
+	})
+
+	t.Run("should log errors tagged with the component name without a logger", func(t *testing.T) {
+		var (
+			logs   syncWriter
+			runs   atomic.Int32
+			worker = unixcycle.Every(time.Millisecond, func(ctx context.Context) error {
+				runs.Add(1)
+				return assert.AnError
+			}).WithErrorPolicy(unixcycle.LogErrors(nil))
+			sut = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+			).Add("cleanup", worker)
+			handle = sut.RunAsync()
+		)
+		require.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond)
+
+		handle.Signal(0)
+		got := <-handle.Done()
+
+		assert.NoError(t, got.Err)
+		assert.Contains(t, logs.String(), `msg="Scheduled job failed" component_name=cleanup error="`+assert.AnError.Error())
+	})
+
+	t.Run("should cancel the run in progress when closed", func(t *testing.T) {
+		var (
+			running = make(chan struct{})
+			once    sync.Once
+			sut     = unixcycle.Every(time.Millisecond, func(ctx context.Context) error {
+				once.Do(func() { close(running) })
+				<-ctx.Done()
+				return nil
+			})
+			result = make(chan error, 1)
+		)
+		go func() { result <- sut.Start() }()
+		<-running
+
+		require.NoError(t, sut.Close())
+
+		assert.NoError(t, <-result)
+	})
+
+	t.Run("should start again after being set up", func(t *testing.T) {
+		var (
+			runs atomic.Int32
+			sut  = unixcycle.Every(time.Millisecond, func(ctx context.Context) error {
+				runs.Add(1)
+				return nil
+			})
+			result = make(chan error, 1)
+		)
+		require.NoError(t, sut.Close())
+		require.NoError(t, sut.Setup())
+
+		go func() { result <- sut.Start() }()
+		require.Eventually(t, func() bool { return runs.Load() >= 1 }, time.Second, time.Millisecond)
+
+		require.NoError(t, sut.Close())
+		assert.NoError(t, <-result)
+	})
+}
+
+// syncWriter is a buffer serializing writes and reads, so it can be read while it is still written to
+type syncWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncWriter) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.String()
+}
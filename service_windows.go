@@ -0,0 +1,53 @@
+//go:build windows
+
+package unixcycle
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/windows/svc"
+)
+
+var _ svc.Handler = &windowsService{}
+
+// windowsService runs the manager as a Windows service, translating Stop and Shutdown requests into a graceful shutdown
+type windowsService struct {
+	manager *Manager
+}
+
+// ServiceHandler returns a svc.Handler running the manager, for use with svc.Run or svc/debug.Run.
+// Stop and Shutdown requests of the service control manager shut the manager down like SIGTERM does
+func (m *Manager) ServiceHandler() svc.Handler {
+	return &windowsService{manager: m}
+}
+
+// RunWindowsService runs the manager as the Windows service name until it is stopped.
+// Use svc.IsWindowsService to decide between this and Run
+func (m *Manager) RunWindowsService(name string) error {
+	return svc.Run(name, m.ServiceHandler())
+}
+
+func (w *windowsService) Execute(args []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+	var (
+		handle = w.manager.RunAsync()
+		done   = handle.Done()
+	)
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+
+	for {
+		select {
+		case result := <-done:
+			changes <- svc.Status{State: svc.Stopped}
+			return false, uint32(result.ExitCode)
+		case request := <-requests:
+			switch request.Cmd {
+			case svc.Interrogate:
+				changes <- request.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				handle.Signal(int(syscall.SIGTERM))
+			}
+		}
+	}
+}
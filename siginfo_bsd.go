@@ -0,0 +1,35 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package unixcycle
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyStatusOnInfoSignal writes the Report to w every time SIGINFO (Ctrl+T) is received, until stop is called
+func (m *Manager) notifyStatusOnInfoSignal(w io.Writer) (stop func()) {
+	var (
+		signals = make(chan os.Signal, 1)
+		done    = make(chan struct{})
+	)
+	signal.Notify(signals, syscall.SIGINFO)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-signals:
+				_, _ = io.WriteString(w, m.Report())
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
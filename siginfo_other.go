@@ -0,0 +1,10 @@
+//go:build !(darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package unixcycle
+
+import "io"
+
+// notifyStatusOnInfoSignal is a no-op, SIGINFO only exists on BSD derived platforms
+func (m *Manager) notifyStatusOnInfoSignal(io.Writer) (stop func()) {
+	return func() {}
+}
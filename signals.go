@@ -0,0 +1,72 @@
+package unixcycle
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+)
+
+type signalHook struct {
+	signal os.Signal
+	hook   func(os.Signal) error
+}
+
+// OnSignal runs hook every time sig is received while the components are running, e.g. to dump state,
+// rotate logs or toggle debug logging. Hooks run one at a time, in the order they were registered.
+// Registering SIGINT or SIGTERM does not change their shutdown semantics, the hook runs in addition
+func (m *Manager) OnSignal(sig os.Signal, hook func(os.Signal) error) *Manager {
+	m.signalHooks = append(m.signalHooks, signalHook{signal: sig, hook: hook})
+	return m
+}
+
+// runSignalHooks runs the hooks registered with OnSignal until stop is called
+func (m *Manager) runSignalHooks() (stop func()) {
+	var signals []os.Signal
+	for _, h := range m.signalHooks {
+		if !slices.Contains(signals, h.signal) {
+			signals = append(signals, h.signal)
+		}
+	}
+
+	return m.handleSignals(signals, func(sig os.Signal) {
+		for _, h := range m.signalHooks {
+			if h.signal != sig {
+				continue
+			}
+			if err, _ := callRecovering(func() error { return h.hook(sig) }); err != nil {
+				m.logError(CodeSignalHookFailed, fmt.Sprintf("Failure in hook for signal %v: %v", sig, err), slog.String("signal", sig.String()))
+			}
+		}
+	})
+}
+
+// handleSignals calls handle every time one of signals is received, one signal at a time, until stop is called
+func (m *Manager) handleSignals(signals []os.Signal, handle func(os.Signal)) (stop func()) {
+	if len(signals) == 0 {
+		return func() {}
+	}
+
+	var (
+		received = make(chan os.Signal, 1)
+		done     = make(chan struct{})
+	)
+	signal.Notify(received, signals...)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-received:
+				handle(sig)
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(received)
+		close(done)
+	}
+}
@@ -0,0 +1,45 @@
+//go:build linux
+
+package unixcycle_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestOnSignal(t *testing.T) {
+	t.Run("should run hooks of a received signal without shutting down", func(t *testing.T) {
+		var (
+			received = make(chan os.Signal, 2)
+			hook     = func(sig os.Signal) error { received <- sig; return nil }
+			sut      = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).
+					Add("worker", unixcycle.Starter(func() error { return nil })).
+					OnSignal(syscall.SIGUSR2, hook).
+					OnSignal(syscall.SIGUSR2, func(os.Signal) error { return assert.AnError }).
+					OnSignal(syscall.SIGUSR2, hook)
+			handle = sut.RunAsync()
+		)
+		require.Eventually(t, func() bool {
+			return unixcycle.ManagerReadyProber(sut)(context.Background()) == nil
+		}, time.Second, time.Millisecond)
+
+		require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR2))
+
+		assert.Equal(t, syscall.SIGUSR2, <-received)
+		assert.Equal(t, syscall.SIGUSR2, <-received, "a failing hook should not stop the others")
+		select {
+		case <-handle.Done():
+			t.Fatal("manager should still be running")
+		default:
+		}
+		handle.Signal(int(syscall.SIGTERM))
+		<-handle.Done()
+	})
+}
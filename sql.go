@@ -0,0 +1,116 @@
+package unixcycle
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var _ Component = &sqlComponent{}
+
+// sqlComponent owns a database/sql connection pool
+type sqlComponent struct {
+	name           string
+	open           func() (*sql.DB, error)
+	healthInterval time.Duration
+
+	mu      sync.Mutex
+	db      *sql.DB
+	lastErr error // Of the last periodic ping
+	stop    chan struct{}
+}
+
+// SQL creates a component owning the connection pool returned by open:
+//   - Setup opens the pool and pings the database, so an unreachable database fails the setup
+//   - Health pings the database, see Manager.Health
+//   - Close closes the pool
+//
+// Use DB to get the pool once the component is set up
+func SQL(name string, open func() (*sql.DB, error)) *sqlComponent {
+	return &sqlComponent{name: name, open: open}
+}
+
+// WithHealthInterval pings the database every interval while running, and makes Health report the result of the
+// last ping instead of pinging on every health check. Useful when health checks are frequent
+func (s *sqlComponent) WithHealthInterval(interval time.Duration) *sqlComponent {
+	s.healthInterval = interval
+	return s
+}
+
+func (s *sqlComponent) Setup() error {
+	db, err := s.open()
+	if err != nil {
+		return fmt.Errorf("opening database %s: %w", s.name, err)
+	}
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("pinging database %s: %w", s.name, errors.Join(err, db.Close()))
+	}
+
+	s.mu.Lock()
+	s.db, s.lastErr, s.stop = db, nil, make(chan struct{})
+	s.mu.Unlock()
+	return nil
+}
+
+// Start pings the database periodically when a health interval is set, and returns right away otherwise
+func (s *sqlComponent) Start() error {
+	if s.healthInterval <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	db, stop := s.db, s.stop
+	s.mu.Unlock()
+
+	t := time.NewTicker(s.healthInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-t.C:
+			ctx, cancel := context.WithTimeout(context.Background(), s.healthInterval)
+			err := db.PingContext(ctx)
+			cancel()
+
+			s.mu.Lock()
+			s.lastErr = err
+			s.mu.Unlock()
+		}
+	}
+}
+
+// Health pings the database, or reports the last periodic ping when a health interval is set
+func (s *sqlComponent) Health(ctx context.Context) error {
+	s.mu.Lock()
+	db, lastErr := s.db, s.lastErr
+	s.mu.Unlock()
+
+	if s.healthInterval > 0 {
+		return lastErr
+	}
+	return db.PingContext(ctx)
+}
+
+func (s *sqlComponent) Close() error {
+	s.mu.Lock()
+	db, stop := s.db, s.stop
+	s.mu.Unlock()
+	if db == nil {
+		return nil
+	}
+
+	close(stop)
+	return db.Close()
+}
+
+// DB returns the connection pool, nil before the component is set up
+func (s *sqlComponent) DB() *sql.DB {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.db
+}
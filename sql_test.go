@@ -0,0 +1,126 @@
+package unixcycle_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+// fakeDriver is a database/sql driver whose pings fail while down is set
+type fakeDriver struct {
+	down  atomic.Bool
+	pings atomic.Int32
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{driver: d}, nil }
+
+type fakeConn struct{ driver *fakeDriver }
+
+func (c *fakeConn) Ping(context.Context) error {
+	c.driver.pings.Add(1)
+	if c.driver.down.Load() {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+var driverCount atomic.Int32
+
+func openFake(t *testing.T) (*fakeDriver, func() (*sql.DB, error)) {
+	t.Helper()
+	d, name := registerFake(t)
+	return d, func() (*sql.DB, error) { return sql.Open(name, "") }
+}
+
+// registerFake registers a new fakeDriver, returning it and the name it is registered under
+func registerFake(t *testing.T) (*fakeDriver, string) {
+	t.Helper()
+	d := &fakeDriver{}
+	name := "fake" + string(rune('a'+driverCount.Add(1)))
+	sql.Register(name, d)
+	return d, name
+}
+
+func TestSQL(t *testing.T) {
+	t.Run("should open and ping the database during setup, and close it on close", func(t *testing.T) {
+		var (
+			d, open = openFake(t)
+			sut     = unixcycle.SQL("orders", open)
+		)
+
+		assert.Nil(t, sut.DB())
+		require.NoError(t, sut.Setup())
+		require.NotNil(t, sut.DB())
+		assert.EqualValues(t, 1, d.pings.Load())
+		assert.NoError(t, sut.Start())
+		assert.NoError(t, sut.Health(context.Background()))
+
+		assert.NoError(t, sut.Close())
+		assert.Error(t, sut.DB().Ping(), "the pool should be closed")
+	})
+
+	t.Run("should fail setup when the database can't be reached", func(t *testing.T) {
+		var (
+			d, open = openFake(t)
+			sut     = unixcycle.SQL("orders", open)
+		)
+		d.down.Store(true)
+
+		err := sut.Setup()
+
+		assert.ErrorIs(t, err, driver.ErrBadConn)
+		assert.ErrorContains(t, err, "orders")
+	})
+
+	t.Run("should fail setup when the database can't be opened", func(t *testing.T) {
+		sut := unixcycle.SQL("orders", func() (*sql.DB, error) { return nil, assert.AnError })
+
+		assert.ErrorIs(t, sut.Setup(), assert.AnError)
+	})
+
+	t.Run("should report the last periodic ping as health", func(t *testing.T) {
+		var (
+			d, open = openFake(t)
+			sut     = unixcycle.SQL("orders", open).WithHealthInterval(10 * time.Millisecond)
+			result  = make(chan error, 1)
+		)
+		require.NoError(t, sut.Setup())
+		go func() { result <- sut.Start() }()
+
+		d.down.Store(true)
+		assert.Eventually(t, func() bool { return sut.Health(context.Background()) != nil }, time.Second, 5*time.Millisecond)
+		d.down.Store(false)
+		assert.Eventually(t, func() bool { return sut.Health(context.Background()) == nil }, time.Second, 5*time.Millisecond)
+
+		assert.NoError(t, sut.Close())
+		assert.NoError(t, <-result)
+	})
+
+	t.Run("should be reported by the manager's health", func(t *testing.T) {
+		var (
+			d, open = openFake(t)
+			sut     = unixcycle.NewManager().Add("orders", unixcycle.SQL("orders", open))
+			handle  = sut.RunAsync()
+		)
+		defer func() { handle.Signal(int(syscall.SIGTERM)); <-handle.Done() }()
+		d.down.Store(true)
+
+		assert.Eventually(t, func() bool {
+			report := sut.Health(context.Background())
+			return !report.Healthy && len(report.Components) == 1 && report.Components[0].Err != nil
+		}, time.Second, 5*time.Millisecond)
+	})
+}
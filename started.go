@@ -0,0 +1,102 @@
+package unixcycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WithOnStarted registers a hook that runs once all components are started and ready, receiving how long the startup took
+// since Run was called. Useful for flipping readiness, logging the startup time or kicking off warmup tasks.
+// Hooks are run in the order they are registered, and shutdown waits for them, so long running tasks should be started in a goroutine
+func WithOnStarted(hook func(startup time.Duration)) managerOption {
+	return func(o *managerOptions) {
+		o.onStarted = append(o.onStarted, hook)
+	}
+}
+
+// WithReadinessProber runs prober once all components are started and ready, and only considers the manager started
+// once it succeeds: before the started hooks run, Started is closed and systemd is notified, see WithSystemdNotify.
+// The prober gets the setup timeout, see WithSetupTimeout. When it fails, the manager shuts down like when a component
+// fails to become ready. Use it to check the service end to end, e.g. with HTTPProber on its own health endpoint
+func WithReadinessProber(prober ProberFunc) managerOption {
+	return func(o *managerOptions) {
+		o.readinessProber = prober
+	}
+}
+
+// Started is closed once all components are started and ready, see WithOnStarted.
+// It is never closed if the startup fails or is interrupted by shutdown
+func (m *Manager) Started() <-chan struct{} {
+	return m.started
+}
+
+// componentsStarted is called once all components are started on run, but not on restarts
+func (m *Manager) componentsStarted(runBegan time.Time) {
+	startup := time.Since(runBegan)
+	m.logInfo(CodeStarted, fmt.Sprintf("Started %d components in %s", len(m.components), startup))
+
+	for _, hook := range m.onStarted {
+		hook(startup)
+	}
+	close(m.started)
+	m.emit(EventAllStarted, "", CodeStarted, nil)
+	m.systemdReady()
+	upgradeReady()
+}
+
+// WaitForReady waits for the manager to be started, see Started, and then runs prober with ctx,
+// e.g. for a test or a canary to check the service end to end before sending traffic. Unlike WithReadinessProber,
+// the prober is bound only by ctx, not the setup timeout, and a failing prober is only returned and doesn't shut
+// the manager down. Wrap prober in RetryingProber to keep trying. If the startup fails, it waits until ctx is done
+func (m *Manager) WaitForReady(ctx context.Context, prober ProberFunc) error {
+	select {
+	case <-m.Started():
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for the manager to start: %w", context.Cause(ctx))
+	}
+
+	if err := prober(ctx); err != nil {
+		return fmt.Errorf("%w: readiness prober: %w", ErrNotReady, err)
+	}
+	return nil
+}
+
+// probeReadiness runs the readiness prober, see WithReadinessProber.
+// Returns false if it failed or the components are being stopped
+func (m *Manager) probeReadiness() bool {
+	if m.readinessProber == nil {
+		return true
+	}
+
+	runCtx, _ := m.currentRun()
+	err := m.runReadinessProber(runCtx, m.readinessProber)
+	if runCtx.Err() != nil {
+		return false // Stopped for a restart or shutdown while probing
+	}
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, errTimeout) {
+		m.logError(CodeReadinessProbeFailed, fmt.Sprintf("Readiness prober did not succeed within %s: %v", m.setupTimeout, err))
+	} else {
+		m.logError(CodeReadinessProbeFailed, fmt.Sprintf("Readiness prober failed: %v", err))
+	}
+	m.abort(fmt.Errorf("%w: readiness prober: %w", ErrNotReady, err))
+	return false
+}
+
+// runReadinessProber runs prober within the setup timeout. A prober timed out by its context
+// wraps errTimeout all the same, so it is treated like any other timeout
+func (m *Manager) runReadinessProber(ctx context.Context, prober ProberFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, m.setupTimeout)
+	defer cancel()
+
+	err := funcOrTimeout(func() error { return prober(ctx) }, m.setupTimeout)
+	if errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, errTimeout) {
+		err = fmt.Errorf("%w: %w", errTimeout, err)
+	}
+	return err
+}
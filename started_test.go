@@ -0,0 +1,229 @@
+package unixcycle_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestStarted(t *testing.T) {
+	t.Run("should run the started hooks in order and close Started once all components are ready", func(t *testing.T) {
+		var (
+			calls   = make(chan string, 3)
+			hooks   []string
+			startup time.Duration
+			sut     = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithOnStarted(func(d time.Duration) { hooks = append(hooks, "first"); startup = d }),
+				unixcycle.WithOnStarted(func(time.Duration) { hooks = append(hooks, "second") }),
+			).
+				Add("db", newReadyComponent(calls, nil))
+			handle = sut.RunAsync()
+		)
+		defer func() {
+			handle.Signal(int(syscall.SIGTERM))
+			<-handle.Done()
+		}()
+
+		select {
+		case <-sut.Started():
+		case <-time.After(time.Second):
+			require.Fail(t, "components should be started")
+		}
+		assert.Equal(t, "start db", <-calls)
+		assert.Equal(t, "db ready", <-calls, "should be ready before Started is closed")
+		assert.Equal(t, []string{"first", "second"}, hooks)
+		assert.Positive(t, startup)
+	})
+
+	t.Run("should not close Started when the startup fails", func(t *testing.T) {
+		var (
+			hookCalled = false
+			sut        = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithOnStarted(func(time.Duration) { hookCalled = true }),
+			).
+				Add("db", newReadyComponent(make(chan string, 2), errors.New("no connection")))
+		)
+
+		got := sut.RunWithResult()
+
+		assert.ErrorIs(t, got.Err, unixcycle.ErrNotReady)
+		assert.False(t, hookCalled)
+		select {
+		case <-sut.Started():
+			assert.Fail(t, "Started should not be closed")
+		default:
+		}
+	})
+
+	t.Run("should only consider the manager started once the readiness prober succeeded", func(t *testing.T) {
+		var (
+			calls    = make(chan string, 2)
+			probing  = make(chan struct{})
+			release  = make(chan struct{})
+			hookRuns atomic.Int32
+			sut      = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithOnStarted(func(time.Duration) { hookRuns.Add(1) }),
+				unixcycle.WithReadinessProber(func(ctx context.Context) error {
+					close(probing)
+					<-release
+					return nil
+				}),
+			).
+				Add("db", newReadyComponent(calls, nil))
+			handle = sut.RunAsync()
+		)
+		defer func() {
+			handle.Signal(int(syscall.SIGTERM))
+			<-handle.Done()
+		}()
+
+		<-probing
+		assert.Equal(t, "start db", <-calls)
+		assert.Equal(t, "db ready", <-calls, "should probe once the components are ready")
+		select {
+		case <-sut.Started():
+			assert.Fail(t, "Started should not be closed while probing")
+		case <-time.After(50 * time.Millisecond):
+		}
+		assert.Zero(t, hookRuns.Load())
+
+		close(release)
+		select {
+		case <-sut.Started():
+		case <-time.After(time.Second):
+			require.Fail(t, "manager should be started once the prober succeeded")
+		}
+		assert.EqualValues(t, 1, hookRuns.Load())
+	})
+
+	t.Run("should shut down when the readiness prober fails", func(t *testing.T) {
+		var (
+			hookCalled = false
+			sut        = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithOnStarted(func(time.Duration) { hookCalled = true }),
+				unixcycle.WithReadinessProber(func(ctx context.Context) error { return errors.New("health endpoint unreachable") }),
+			).
+				Add("db", newReadyComponent(make(chan string, 2), nil))
+		)
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, int(syscall.SIGABRT), got.Signal)
+		assert.ErrorIs(t, got.Err, unixcycle.ErrNotReady)
+		assert.ErrorContains(t, got.Err, "readiness prober: health endpoint unreachable")
+		assert.False(t, hookCalled)
+	})
+
+	t.Run("should time out the readiness prober with the setup timeout", func(t *testing.T) {
+		sut := unixcycle.NewManager(
+			unixcycle.WithLifetime(func() int { select {} }),
+			unixcycle.WithSetupTimeout(50*time.Millisecond),
+			unixcycle.WithReadinessProber(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}),
+		).
+			Add("db", newReadyComponent(make(chan string, 2), nil))
+
+		got := sut.RunWithResult()
+
+		assert.Equal(t, int(syscall.SIGALRM), got.Signal)
+		assert.ErrorIs(t, got.Err, unixcycle.ErrNotReady)
+	})
+
+	t.Run("should wait for the manager to start before probing its readiness", func(t *testing.T) {
+		var (
+			calls  = make(chan string, 2)
+			sut    = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).Add("db", newReadyComponent(calls, nil))
+			probed = false
+			prober = func(ctx context.Context) error {
+				select {
+				case <-sut.Started():
+					probed = true
+					return nil
+				default:
+					return errors.New("probed before the manager started")
+				}
+			}
+			handle = sut.RunAsync()
+		)
+		defer func() {
+			handle.Signal(int(syscall.SIGTERM))
+			<-handle.Done()
+		}()
+
+		err := sut.WaitForReady(context.Background(), prober)
+
+		assert.NoError(t, err)
+		assert.True(t, probed)
+	})
+
+	t.Run("should return the error of the prober without shutting down", func(t *testing.T) {
+		var (
+			sut    = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} })).Add("db", newReadyComponent(make(chan string, 2), nil))
+			handle = sut.RunAsync()
+		)
+		defer func() {
+			handle.Signal(int(syscall.SIGTERM))
+			<-handle.Done()
+		}()
+
+		err := sut.WaitForReady(context.Background(), func(ctx context.Context) error { return assert.AnError })
+
+		assert.ErrorIs(t, err, unixcycle.ErrNotReady)
+		assert.ErrorIs(t, err, assert.AnError)
+		select {
+		case <-handle.Done():
+			assert.Fail(t, "manager should keep running")
+		default:
+		}
+	})
+
+	t.Run("should not bound the prober by the setup timeout", func(t *testing.T) {
+		var (
+			sut = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithSetupTimeout(10*time.Millisecond),
+			).Add("db", newReadyComponent(make(chan string, 2), nil))
+			handle = sut.RunAsync()
+		)
+		defer func() {
+			handle.Signal(int(syscall.SIGTERM))
+			<-handle.Done()
+		}()
+
+		err := sut.WaitForReady(context.Background(), func(ctx context.Context) error {
+			select {
+			case <-time.After(50 * time.Millisecond):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should stop waiting for the manager to start once the context is done", func(t *testing.T) {
+		var (
+			sut         = unixcycle.NewManager()
+			ctx, cancel = context.WithCancel(context.Background())
+		)
+		cancel()
+
+		err := sut.WaitForReady(ctx, func(ctx context.Context) error { return nil })
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,117 @@
+package unixcycle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// componentStates is the on-disk format of the state file, keyed by component name
+type componentStates map[string][]byte
+
+func (m *Manager) restoreComponentStates() error {
+	if m.stateFile == "" {
+		return nil
+	}
+
+	states, err := readStateFile(m.stateFile)
+	if err != nil {
+		m.logError(CodeStateReadFailed, fmt.Sprintf("Failure reading state file %q: %v", m.stateFile, err), slog.String("state_file", m.stateFile))
+		return err
+	}
+
+	for _, s := range m.components {
+		saver, ok := s.Component.(StateSaver)
+		if !ok {
+			continue
+		}
+		state, ok := states[s.name]
+		if !ok {
+			continue
+		}
+		m.logInfo(CodeStateRestoreBegin, fmt.Sprintf("Restoring state for component %q", s.name), slog.String("component_name", s.name))
+		if err := saver.RestoreState(state); err != nil {
+			m.logError(CodeStateRestoreFailed, fmt.Sprintf("Failure restoring state for component %q: %v", s.name, err), slog.String("component_name", s.name))
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) saveComponentStates() error {
+	if m.stateFile == "" {
+		return nil
+	}
+
+	var (
+		states = componentStates{}
+		errs   []error
+	)
+	for _, s := range m.components {
+		saver, ok := s.Component.(StateSaver)
+		if !ok {
+			continue
+		}
+		m.logInfo(CodeStateSaveBegin, fmt.Sprintf("Saving state for component %q", s.name), slog.String("component_name", s.name))
+		state, err := saver.SaveState()
+		if err != nil {
+			m.logError(CodeStateSaveFailed, fmt.Sprintf("Failure saving state for component %q: %v", s.name, err), slog.String("component_name", s.name))
+			errs = append(errs, err) // Only this component loses its state, the others are still written
+			continue
+		}
+		states[s.name] = state
+	}
+
+	if err := writeStateFile(m.stateFile, states); err != nil {
+		m.logError(CodeStateWriteFailed, fmt.Sprintf("Failure writing state file %q: %v", m.stateFile, err), slog.String("state_file", m.stateFile))
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// readStateFile returns an empty set of states if the file does not exist yet, e.g. on the very first run
+func readStateFile(path string) (componentStates, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return componentStates{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	states := componentStates{}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("decoding state file: %w", err)
+	}
+
+	return states, nil
+}
+
+// writeStateFile writes to a temporary file first and renames it, so a crash mid-write never leaves a corrupt state file behind
+func writeStateFile(path string, states componentStates) error {
+	data, err := json.Marshal(states)
+	if err != nil {
+		return fmt.Errorf("encoding state file: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
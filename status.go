@@ -0,0 +1,282 @@
+package unixcycle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"slices"
+	"text/tabwriter"
+	"time"
+)
+
+// maxRecentEvents bounds the number of log events kept around for the status page
+const maxRecentEvents = 50
+
+// maxTransitions bounds the number of state transitions kept per component, e.g. when it keeps being restarted
+const maxTransitions = 50
+
+// ComponentState is the lifecycle state of a component
+type ComponentState string
+
+const (
+	StateRegistered ComponentState = "registered"
+	StateSettingUp  ComponentState = "setting up"
+	StateSetUp      ComponentState = "set up"
+	StateRunning    ComponentState = "running"
+	StateStopped    ComponentState = "stopped"  // Start returned without an error
+	StateDraining   ComponentState = "draining" // Still running while Drain is called on shutdown
+	StateClosing    ComponentState = "closing"
+	StateClosed     ComponentState = "closed"
+	StateFailed     ComponentState = "failed"
+	StateStandby    ComponentState = "standby" // Leader-only and waiting for leadership, see LeaderOnly
+)
+
+// StateTransition is a state a component entered, and when
+type StateTransition struct {
+	State ComponentState
+	Time  time.Time
+}
+
+type componentStatus struct {
+	state       ComponentState
+	since       time.Time
+	startedAt   time.Time
+	lastErr     error
+	timings     ComponentTimings
+	transitions []StateTransition
+
+	startExited bool // Whether the Start goroutine has returned
+	startErr    error
+	exited      chan struct{} // Closed once the Start goroutine of the latest launch returns
+	cancel      context.CancelFunc
+	restarting  bool // Whether Start returning is expected, see Manager.Restart
+}
+
+type statusEvent struct {
+	time    time.Time
+	level   slog.Level
+	code    Code
+	message string
+}
+
+// componentStatusView is a copy of a componentStatus that is safe to read without holding the lock
+type componentStatusView struct {
+	Name      string
+	State     ComponentState
+	Since     time.Time
+	Uptime    time.Duration
+	LastError string
+}
+
+// setState moves the component into state, emitting the matching event with the code of the log line describing it
+func (m *Manager) setState(s namedComponent, state ComponentState, code Code, err error) {
+	if m.updateState(s, state, err) {
+		m.emit(eventKinds[state], s.name, code, err)
+	}
+}
+
+// updateState returns whether the state of the component changed
+func (m *Manager) updateState(s namedComponent, state ComponentState, err error) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if state == StateStopped && s.status.state != StateRunning {
+		return false // Start returning is expected once the component is being drained or closed
+	}
+	if state == StateDraining && s.status.state != StateRunning {
+		return false // Only running components are drained, stopped or failed ones keep their state
+	}
+
+	now := time.Now()
+	if s.status.running() && state != StateDraining {
+		s.status.timings.Uptime = now.Sub(s.status.startedAt)
+	}
+	s.status.enter(state, now)
+	if state == StateRunning {
+		s.status.startedAt = now
+	}
+	if err != nil {
+		s.status.lastErr = err
+	}
+	return true
+}
+
+func (s *componentStatus) enter(state ComponentState, now time.Time) {
+	s.state = state
+	s.since = now
+	s.transitions = append(s.transitions, StateTransition{State: state, Time: now})
+	if len(s.transitions) > maxTransitions {
+		s.transitions = s.transitions[len(s.transitions)-maxTransitions:]
+	}
+}
+
+// running reports whether Start is still running, including while the component is drained
+func (s *componentStatus) running() bool {
+	return s.state == StateRunning || s.state == StateDraining
+}
+
+func (m *Manager) state(s namedComponent) ComponentState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return s.status.state
+}
+
+// markLaunched keeps what is needed to stop the component again on its own, see Manager.Restart
+func (m *Manager) markLaunched(s namedComponent, cancel context.CancelFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s.status.startExited = false
+	s.status.startErr = nil
+	s.status.exited = make(chan struct{})
+	s.status.cancel = cancel
+}
+
+func (m *Manager) markStartExited(s namedComponent, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s.status.startExited = true
+	s.status.startErr = err
+	close(s.status.exited)
+}
+
+// startExited reports whether the Start goroutine of the component has returned, and with which error
+func (m *Manager) startExited(s namedComponent) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return s.status.startExited, s.status.startErr
+}
+
+func (m *Manager) componentStatuses() []componentStatusView {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	views := make([]componentStatusView, 0, len(m.components))
+	for _, s := range m.components {
+		view := componentStatusView{
+			Name:  s.name,
+			State: s.status.state,
+			Since: s.status.since,
+		}
+		if s.status.running() {
+			view.Uptime = time.Since(s.status.startedAt).Round(time.Second)
+		}
+		if s.status.lastErr != nil {
+			view.LastError = s.status.lastErr.Error()
+		}
+		views = append(views, view)
+	}
+
+	return views
+}
+
+// componentErrors returns the last error of every component that failed
+func (m *Manager) componentErrors() map[string]error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	errs := map[string]error{}
+	for _, s := range m.components {
+		if s.status.lastErr != nil {
+			errs[s.name] = s.status.lastErr
+		}
+	}
+	return errs
+}
+
+func (m *Manager) recordEvent(level slog.Level, code Code, msg string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.recentEvents = append(m.recentEvents, statusEvent{time: time.Now(), level: level, code: code, message: msg})
+	if len(m.recentEvents) > maxRecentEvents {
+		m.recentEvents = m.recentEvents[len(m.recentEvents)-maxRecentEvents:]
+	}
+}
+
+func (m *Manager) recentEventsCopy() []statusEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	events := make([]statusEvent, len(m.recentEvents))
+	copy(events, m.recentEvents)
+	return events
+}
+
+// Report renders a plain text table of the components, their states, how long they have been in them,
+// uptimes and last errors, e.g. to print on SIGUSR1 or serve from an admin endpoint when a service seems stuck
+func (m *Manager) Report() string {
+	var (
+		buf bytes.Buffer
+		w   = tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	)
+	fmt.Fprintln(w, "COMPONENT\tSTATE\tFOR\tUPTIME\tLAST ERROR")
+	for _, c := range m.componentStatuses() {
+		uptime := "-"
+		if c.Uptime > 0 {
+			uptime = c.Uptime.String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Name, c.State, time.Since(c.Since).Round(time.Second), uptime, c.LastError)
+	}
+	_ = w.Flush()
+
+	return buf.String()
+}
+
+var statusPage = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html>
+<head><title>UnixCycle status</title></head>
+<body>
+<h1>Components</h1>
+<table border="1" cellpadding="4">
+<tr><th>Name</th><th>State</th><th>Since</th><th>Uptime</th><th>Last error</th></tr>
+{{range .Components}}<tr><td>{{.Name}}</td><td>{{.State}}</td><td>{{if not .Since.IsZero}}{{.Since.Format "2006-01-02 15:04:05"}}{{end}}</td><td>{{if .Uptime}}{{.Uptime}}{{end}}</td><td>{{.LastError}}</td></tr>
+{{end}}</table>
+<h1>Recent events</h1>
+<table border="1" cellpadding="4">
+<tr><th>Time</th><th>Level</th><th>Code</th><th>Message</th></tr>
+{{range .Events}}<tr><td>{{.Time.Format "2006-01-02 15:04:05"}}</td><td>{{.Level}}</td><td>{{.Code}}</td><td>{{.Message}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// StatusHandler returns a handler rendering a human-readable status page of the manager:
+// components, their states, uptimes and last errors, as well as the most recent events.
+// Mount it on an existing admin mux, e.g. mux.Handle("/status", manager.StatusHandler())
+func (m *Manager) StatusHandler() http.Handler {
+	type eventView struct {
+		Time    time.Time
+		Level   slog.Level
+		Code    Code
+		Message string
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			recent = m.recentEventsCopy()
+			events = make([]eventView, 0, len(recent))
+		)
+		for _, e := range slices.Backward(recent) { // Newest first
+			events = append(events, eventView{Time: e.time, Level: e.level, Code: e.code, Message: e.message})
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err := statusPage.Execute(w, struct {
+			Components []componentStatusView
+			Events     []eventView
+		}{
+			Components: m.componentStatuses(),
+			Events:     events,
+		})
+		if err != nil {
+			m.logError(CodeStatusPageFailed, "Failure rendering status page", "error", err)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package unixcycle_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestStatusHandler(t *testing.T) {
+	t.Run("should render components, states and recent events", func(t *testing.T) {
+		var (
+			started  = make(chan struct{})
+			stop     = make(chan struct{})
+			shutdown = make(chan int, 1)
+			sut      = unixcycle.NewManager(unixcycle.WithLifetime(func() int { return <-shutdown })).
+					Add("worker", unixcycle.Starter(func() error { close(started); <-stop; return nil })).
+					Add("broken closer", unixcycle.Closer(func() error { return assert.AnError }))
+			done = make(chan int)
+		)
+		go func() { done <- sut.Run() }()
+		<-started
+
+		rec := httptest.NewRecorder()
+		sut.StatusHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "<td>worker</td><td>running</td>")
+		assert.Contains(t, rec.Body.String(), "Starting component &#34;worker&#34;")
+
+		close(stop)
+		shutdown <- 0
+		<-done
+
+		rec = httptest.NewRecorder()
+		sut.StatusHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+		assert.Contains(t, rec.Body.String(), "<td>broken closer</td><td>failed</td>")
+		assert.Contains(t, rec.Body.String(), assert.AnError.Error())
+	})
+}
+
+func TestReport(t *testing.T) {
+	t.Run("should render a table of components, states, uptimes and last errors", func(t *testing.T) {
+		sut := unixcycle.NewManager(unixcycle.WithLifetime(func() int { return 0 })).
+			Add("broken closer", unixcycle.Closer(func() error { return assert.AnError })).
+			Add("closer", unixcycle.Closer(func() error { return nil }))
+		sut.Run()
+
+		got := sut.Report()
+
+		assert.Equal(t, strings.Join([]string{
+			"COMPONENT      STATE   FOR  UPTIME  LAST ERROR",
+			"broken closer  failed  0s   -       assert.AnError general error for testing",
+			"closer         closed  0s   -       ",
+			"",
+		}, "\n"), got)
+	})
+}
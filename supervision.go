@@ -0,0 +1,253 @@
+package unixcycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// SupervisionStrategy decides what happens when the Start of a component fails (returns an error or panics)
+type SupervisionStrategy int
+
+const (
+	// NoSupervision shuts the manager down with SIGABRT as soon as any component fails
+	NoSupervision SupervisionStrategy = iota
+	// OneForOne restarts only the failed component
+	OneForOne
+	// OneForAll closes all components and sets up and starts all of them again
+	OneForAll
+)
+
+type supervisionOptions struct {
+	strategy    SupervisionStrategy
+	backoff     Backoff
+	maxRestarts int
+}
+
+// allComponentsKey is the restart counter key used when all components are restarted together
+const allComponentsKey = ""
+
+// defaultRestartDelay is the delay before a restart outside of the supervision strategy, when there is no supervision backoff
+const defaultRestartDelay = time.Second
+
+// WithSupervision restarts failed components Erlang-style instead of shutting down.
+// Restarts wait for backoff before every attempt, and once a component (or for OneForAll, the group) has been
+// restarted maxRestarts times the next failure shuts the manager down with SIGABRT like without supervision
+// A nil backoff waits one second before every restart
+// Default is NoSupervision
+func WithSupervision(strategy SupervisionStrategy, backoff Backoff, maxRestarts int) managerOption {
+	return func(o *managerOptions) {
+		o.supervision = supervisionOptions{
+			strategy:    strategy,
+			backoff:     backoff,
+			maxRestarts: maxRestarts,
+		}
+	}
+}
+
+// componentFailed applies the supervision strategy to a component whose Start failed
+func (m *Manager) componentFailed(s namedComponent, generation int, err error) {
+	if !m.isCurrent(generation) {
+		return // Failing while being stopped for a restart or shutdown is expected
+	}
+
+	switch m.supervision.strategy {
+	case OneForOne:
+		if attempt, ok := m.nextRestart(s.name); ok {
+			go m.restartOne(s, attempt)
+			return
+		}
+	case OneForAll:
+		if attempt, ok := m.nextRestart(allComponentsKey); ok {
+			go m.restartAll(attempt)
+			return
+		}
+	}
+
+	m.abort(newComponentError(s, ErrStartFailed, err))
+}
+
+// isCurrent reports whether components of generation are still running, and not being stopped for a restart or shutdown
+func (m *Manager) isCurrent(generation int) bool {
+	_, current := m.currentRun()
+	return generation == current && m.shutdownCtx.Err() == nil
+}
+
+// nextRestart counts a restart under key, reporting whether it is still within the allowed number of restarts
+func (m *Manager) nextRestart(key string) (attempt int, ok bool) {
+	attempt = m.countRestart(key)
+	return attempt, attempt <= m.supervision.maxRestarts
+}
+
+// countRestart counts a restart under key, returning the attempt
+func (m *Manager) countRestart(key string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.restarts[key]++
+	return m.restarts[key]
+}
+
+// restartDelay is the delay before a restart outside of the supervision strategy:
+// the supervision backoff if there is one, defaultRestartDelay otherwise
+func (m *Manager) restartDelay(attempt int) time.Duration {
+	if m.supervision.backoff == nil {
+		return defaultRestartDelay
+	}
+	return m.supervision.backoff(attempt)
+}
+
+func (m *Manager) restartOne(s namedComponent, attempt int) {
+	m.relaunch(s, m.restartDelay(attempt),
+		fmt.Sprintf("Restarting component %q (attempt %d)", s.name, attempt), slog.String("component_name", s.name), slog.Int("attempt", attempt))
+}
+
+// relaunch starts a single component again after delay, unless shutdown begins in the meantime
+func (m *Manager) relaunch(s namedComponent, delay time.Duration, msg string, attrs ...any) {
+	if !m.sleepUnlessShutdown(delay) {
+		return
+	}
+
+	m.lifecycle.Lock()
+	defer m.lifecycle.Unlock()
+	if m.shutdownCtx.Err() != nil || m.state(s) == StateStandby {
+		return // Shutting down, or closed for losing the leadership in the meantime
+	}
+
+	m.logInfo(CodeRestart, msg, attrs...)
+	m.launch(s)
+}
+
+func (m *Manager) restartAll(attempt int) {
+	m.lifecycle.Lock()
+	defer m.lifecycle.Unlock()
+	if m.shutdownCtx.Err() != nil {
+		return
+	}
+
+	m.logInfo(CodeRestart, fmt.Sprintf("Restarting all components (attempt %d)", attempt), slog.Int("attempt", attempt))
+
+	// Begin the next generation first, so failures of the components we are about to stop are ignored
+	m.mu.Lock()
+	cancelPrevious := m.cancelRun
+	m.generation++
+	m.runCtx, m.cancelRun = context.WithCancel(m.shutdownCtx)
+	m.mu.Unlock()
+	cancelPrevious()
+
+	if err := m.closeComponents(m.components); err != nil {
+		m.abort(fmt.Errorf("closing components for restart: %w", err))
+		return
+	}
+
+	if !m.sleepUnlessShutdown(m.restartDelay(attempt)) {
+		return
+	}
+
+	setUp, err := m.setupComponents()
+	if err != nil {
+		m.abort(fmt.Errorf("setting up components for restart: %w", errors.Join(err, m.rollbackComponents(setUp))))
+		return
+	}
+
+	m.startComponents()
+}
+
+// sleepUnlessShutdown sleeps for d, returning false if shutdown began in the meantime
+func (m *Manager) sleepUnlessShutdown(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-m.shutdownCtx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// Restart stops a single component, sets it up and starts it again while the other components keep running,
+// e.g. for an operator to recover a component stuck in a bad state. Components depending on it are not restarted.
+// Running, stopped and failed components can be restarted, and restarts on request don't count towards the maximum restarts
+func (m *Manager) Restart(name string) error {
+	m.lifecycle.Lock()
+	defer m.lifecycle.Unlock()
+
+	i := slices.IndexFunc(m.components, func(s namedComponent) bool { return s.name == name })
+	if i < 0 {
+		return fmt.Errorf("unknown component %q", name)
+	}
+	s := m.components[i]
+	if state := m.state(s); state != StateRunning && state != StateStopped && state != StateFailed {
+		return fmt.Errorf("component %q is %s", name, state)
+	}
+	if m.shutdownCtx == nil || m.shutdownCtx.Err() != nil {
+		return fmt.Errorf("component %q can not be restarted, the manager is not running", name)
+	}
+
+	m.logInfo(CodeRestart, fmt.Sprintf("Restarting component %q on request", s.name), slog.String("component_name", s.name))
+	if err := m.stopOne(s); err != nil {
+		return err
+	}
+	if err := m.setupComponent(s); err != nil {
+		return err
+	}
+	if m.shutdownCtx.Err() == nil {
+		m.launch(s)
+	}
+	return nil
+}
+
+// stopOne closes a single component while the others keep running, and waits for its Start goroutine to return.
+// The caller holds the lifecycle lock
+func (m *Manager) stopOne(s namedComponent) error {
+	exited := m.beginRestart(s)
+	defer m.endRestart(s)
+
+	if m.needsClose(s) {
+		if err := m.closeComponent(s); err != nil {
+			return err
+		}
+	}
+	if err := funcOrTimeout(func() error { <-exited; return nil }, m.closeTimeout); err != nil {
+		m.setState(s, StateFailed, CodeCloseTimeout, err)
+		return newComponentError(s, ErrCloseTimeout, fmt.Errorf("start did not return after close: %w", err))
+	}
+	return nil
+}
+
+// beginRestart stops the component from being supervised and cancels it if it is runnable,
+// returning a channel closed once its Start goroutine returned
+func (m *Manager) beginRestart(s namedComponent) <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s.status.restarting = true
+	if s.status.cancel != nil {
+		s.status.cancel()
+	}
+	if s.status.exited == nil { // Never launched, e.g. failed during setup
+		exited := make(chan struct{})
+		close(exited)
+		return exited
+	}
+	return s.status.exited
+}
+
+func (m *Manager) endRestart(s namedComponent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s.status.restarting = false
+}
+
+// isRestarting reports whether the component is being stopped by Restart
+func (m *Manager) isRestarting(s namedComponent) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return s.status.restarting
+}
@@ -0,0 +1,160 @@
+package unixcycle_test
+
+import (
+	"context"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestSupervision(t *testing.T) {
+	var (
+		backoff    = unixcycle.ConstantBackoff(10 * time.Millisecond)
+		newManager = func(strategy unixcycle.SupervisionStrategy, maxRestarts int) (*unixcycle.Manager, func(int)) {
+			shutdownChan := make(chan int, 1)
+			m := unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return <-shutdownChan }),
+				unixcycle.WithSupervision(strategy, backoff, maxRestarts),
+				unixcycle.WithCloseTimeout(100*time.Millisecond),
+			)
+			return m, func(signal int) { shutdownChan <- signal }
+		}
+	)
+
+	t.Run("OneForOne should restart only the failed component", func(t *testing.T) {
+		var (
+			m, shutdown  = newManager(unixcycle.OneForOne, 3)
+			flakyStarts  atomic.Int32
+			stableStarts atomic.Int32
+			stop         = make(chan struct{})
+			flaky        = func() error {
+				if flakyStarts.Add(1) < 3 {
+					return assert.AnError
+				}
+				<-stop
+				return nil
+			}
+			stable = func() error { stableStarts.Add(1); <-stop; return nil }
+			sut    = m.
+				Add("flaky", unixcycle.Starter(flaky)).
+				Add("stable", unixcycle.Starter(stable)).
+				Add("stopper", unixcycle.Closer(func() error { close(stop); return nil }))
+			done = make(chan int)
+		)
+		go func() { done <- sut.Run() }()
+
+		assert.Eventually(t, func() bool { return flakyStarts.Load() == 3 }, time.Second, 10*time.Millisecond)
+		shutdown(0)
+
+		assert.Equal(t, 0, <-done)
+		assert.Equal(t, int32(1), stableStarts.Load())
+	})
+
+	t.Run("OneForOne should abort once the component exceeds its restarts", func(t *testing.T) {
+		var (
+			m, _   = newManager(unixcycle.OneForOne, 2)
+			starts atomic.Int32
+			sut    = m.Add("broken", unixcycle.Starter(func() error { starts.Add(1); return assert.AnError }))
+		)
+
+		got := sut.Run()
+
+		assert.Equal(t, int(syscall.SIGABRT), got)
+		assert.Equal(t, int32(3), starts.Load())
+	})
+
+	t.Run("OneForAll should close, set up and start all components again", func(t *testing.T) {
+		var (
+			m, shutdown = newManager(unixcycle.OneForAll, 1)
+			setups      atomic.Int32
+			closes      atomic.Int32
+			flakyStarts atomic.Int32
+			stop        = make(chan struct{}, 1)
+			sibling     = &testComponent{
+				setupFunc: func() error { setups.Add(1); return nil },
+				startFunc: func() error { <-stop; return nil },
+				closeFunc: func() error { closes.Add(1); stop <- struct{}{}; return nil },
+			}
+			flaky = func() error {
+				if flakyStarts.Add(1) == 1 {
+					return assert.AnError
+				}
+				select {}
+			}
+			sut = m.
+				Add("sibling", sibling).
+				Add("flaky", unixcycle.Starter(flaky))
+			done = make(chan int)
+		)
+		go func() { done <- sut.Run() }()
+
+		assert.Eventually(t, func() bool { return flakyStarts.Load() == 2 }, time.Second, 10*time.Millisecond)
+		shutdown(0)
+
+		assert.Equal(t, 0, <-done)
+		assert.Equal(t, int32(2), setups.Load())
+		assert.Equal(t, int32(2), closes.Load())
+	})
+
+	t.Run("should restart after the default delay without a backoff", func(t *testing.T) {
+		for _, strategy := range []unixcycle.SupervisionStrategy{unixcycle.OneForOne, unixcycle.OneForAll} {
+			var (
+				shutdownChan = make(chan int, 1)
+				starts       atomic.Int32
+				sut          = unixcycle.NewManager(
+					unixcycle.WithLifetime(func() int { return <-shutdownChan }),
+					unixcycle.WithSupervision(strategy, nil, 1),
+				).
+					Add("flaky", unixcycle.Starter(func() error {
+						if starts.Add(1) == 1 {
+							return assert.AnError
+						}
+						select {}
+					}))
+				done = make(chan int)
+			)
+			go func() { done <- sut.Run() }()
+
+			assert.Eventually(t, func() bool { return starts.Load() == 2 }, 3*time.Second, 10*time.Millisecond)
+			shutdownChan <- 0
+
+			assert.Equal(t, 0, <-done)
+		}
+	})
+}
+
+func TestRestart(t *testing.T) {
+	t.Run("should restart a single runnable component while the others keep running", func(t *testing.T) {
+		var (
+			runs     atomic.Int32
+			shutdown = make(chan int, 1)
+			db       = &restartableComponent{}
+			sut      = unixcycle.NewManager(unixcycle.WithLifetime(func() int { return <-shutdown })).
+					Add("db", db).
+					Add("worker", unixcycle.Runner(func(ctx context.Context) error { runs.Add(1); <-ctx.Done(); return ctx.Err() }))
+			done = make(chan unixcycle.Result)
+		)
+		go func() { done <- sut.RunWithResult() }()
+		<-sut.Started()
+
+		require.NoError(t, sut.Restart("worker"))
+		require.Eventually(t, func() bool { return runs.Load() == 2 }, time.Second, time.Millisecond)
+		shutdown <- 0
+
+		got := <-done
+		assert.NoError(t, got.Err, "stopping the component for the restart should not count as a failure")
+		assert.Equal(t, int32(1), db.setups.Load())
+	})
+
+	t.Run("should reject unknown components and components that are not running", func(t *testing.T) {
+		sut := unixcycle.NewManager().Add("db", &restartableComponent{})
+
+		assert.EqualError(t, sut.Restart("nope"), `unknown component "nope"`)
+		assert.EqualError(t, sut.Restart("db"), `component "db" is registered`)
+	})
+}
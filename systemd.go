@@ -0,0 +1,94 @@
+package unixcycle
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// WithSystemdNotify makes the manager a systemd service of Type=notify, talking the sd_notify protocol over NOTIFY_SOCKET:
+// READY=1 once all components are started, STOPPING=1 when shutdown begins and, when the unit has WatchdogSec set,
+// WATCHDOG=1 heartbeats for as long as Manager.Health reports healthy
+// Has no effect when the process is not started by systemd
+func WithSystemdNotify() managerOption {
+	return func(o *managerOptions) {
+		o.systemdNotify = true
+	}
+}
+
+// systemdReady tells systemd the service is ready, once all components are started
+func (m *Manager) systemdReady() {
+	if !m.systemdNotify {
+		return
+	}
+
+	m.notifySystemd("READY=1")
+	if interval, ok := systemdWatchdogInterval(); ok {
+		go m.systemdWatchdog(interval)
+	}
+}
+
+// systemdStopping tells systemd the service is stopping, once shutdown begins
+func (m *Manager) systemdStopping() {
+	if m.systemdNotify {
+		m.notifySystemd("STOPPING=1")
+	}
+}
+
+// systemdWatchdog sends a heartbeat every interval while all components are healthy, until shutdown begins
+func (m *Manager) systemdWatchdog(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-m.shutdownCtx.Done():
+			return
+		case <-t.C:
+			ctx, cancel := context.WithTimeout(m.shutdownCtx, interval)
+			healthy := m.Health(ctx).Healthy
+			cancel()
+			if healthy {
+				m.notifySystemd("WATCHDOG=1")
+			}
+		}
+	}
+}
+
+// systemdWatchdogInterval returns half the watchdog timeout systemd expects heartbeats within, if the watchdog is enabled
+func systemdWatchdogInterval() (time.Duration, bool) {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, false // Meant for another process
+	}
+
+	return time.Duration(usec) * time.Microsecond / 2, true
+}
+
+// notifySystemd sends state to the NOTIFY_SOCKET of systemd, if there is one
+func (m *Manager) notifySystemd(state string) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return
+	}
+
+	err := func() error {
+		conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		_, err = conn.Write([]byte(state))
+		return err
+	}()
+	if err != nil {
+		m.logError(CodeSystemdNotifyFailed, fmt.Sprintf("Failed to notify systemd of %s: %v", state, err))
+	}
+}
@@ -0,0 +1,45 @@
+//go:build linux
+
+package unixcycle_test
+
+import (
+	"net"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestWithSystemdNotify(t *testing.T) {
+	t.Run("should notify systemd of readiness, heartbeats and stopping", func(t *testing.T) {
+		socket := filepath.Join(t.TempDir(), "notify.sock")
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+		require.NoError(t, err)
+		defer conn.Close()
+		t.Setenv("NOTIFY_SOCKET", socket)
+		t.Setenv("WATCHDOG_USEC", "20000")
+
+		var (
+			sut = unixcycle.NewManager(unixcycle.WithLifetime(func() int { select {} }), unixcycle.WithSystemdNotify()).
+				Add("worker", unixcycle.Setup(func() error { return nil }))
+			handle = sut.RunAsync()
+			read   = func() string {
+				buf := make([]byte, 64)
+				n, err := conn.Read(buf)
+				require.NoError(t, err)
+				return string(buf[:n])
+			}
+		)
+
+		assert.Equal(t, "READY=1", read())
+		assert.Equal(t, "WATCHDOG=1", read())
+		handle.Signal(int(syscall.SIGTERM))
+		<-handle.Done()
+		for got := read(); got != "STOPPING=1"; got = read() {
+			assert.Equal(t, "WATCHDOG=1", got)
+		}
+	})
+}
@@ -1,9 +1,14 @@
 package unixcycle
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
-	"syscall"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -23,33 +28,428 @@ func (p ProberFunc) Probe(ctx context.Context) error {
 	return p(ctx)
 }
 
+// NamedProber prefixes the errors of prober with name, so the errors of combinators tell their probers apart,
+// e.g. "parallel prober errored: kafka: connection refused". The attempts made within it are observed with the name
+func NamedProber(name string, prober ProberFunc) ProberFunc {
+	return func(ctx context.Context) error {
+		if err := prober(context.WithValue(ctx, probeNameKey{}, name)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+}
+
+// AnyProber runs the probers in parallel and succeeds as soon as any of them succeeds, cancelling the others,
+// e.g. for a service reachable on either its IPv4 or its IPv6 address. It fails once all of them failed
+func AnyProber(probers ...ProberFunc) ProberFunc {
+	return func(ctx context.Context) error {
+		if len(probers) == 0 {
+			return errors.New("any prober has no probers to succeed")
+		}
+
+		// Every probe is scoped to this call, so probes are cancelled as soon as we return no matter the outcome
+		probeCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		errChan := make(chan error, len(probers)) // Buffered so the probes are reclaimed even if nobody receives
+		for i, probe := range probers {
+			go func() {
+				errChan <- observeProbe(ctx, "any", i+1, func() error { return probe(probeCtx) })
+			}()
+		}
+
+		var errs []error
+		for range probers {
+			select {
+			case err := <-errChan:
+				if err == nil {
+					return nil
+				}
+				errs = append(errs, err)
+			case <-ctx.Done():
+				if ctx.Err() == context.DeadlineExceeded {
+					return fmt.Errorf("any prober timed out: %w", context.Cause(ctx))
+				}
+				return fmt.Errorf("any prober failed: %w", context.Cause(ctx))
+			}
+		}
+		return fmt.Errorf("all probers of any prober failed: %w", errors.Join(errs...))
+	}
+}
+
+// ProbeStage is a named step of StagedProber, e.g. "broker reachable" or "migrations applied"
+type ProbeStage struct {
+	Name   string
+	Prober ProberFunc
+	// Timeout bounds the stage on its own, 0 for no timeout besides the one of the context
+	Timeout time.Duration
+}
+
+// Stage returns a ProbeStage named name, giving prober up to timeout
+func Stage(name string, timeout time.Duration, prober ProberFunc) ProbeStage {
+	return ProbeStage{Name: name, Prober: prober, Timeout: timeout}
+}
+
+// ProbeStageError is returned by StagedProber, naming the stage that failed
+type ProbeStageError struct {
+	Stage string
+	Err   error
+}
+
+func (e *ProbeStageError) Error() string {
+	return fmt.Sprintf("probe stage %q failed: %v", e.Stage, e.Err)
+}
+
+func (e *ProbeStageError) Unwrap() error {
+	return e.Err
+}
+
+// StagedProber runs the stages one after the other, each within its own timeout, and stops at the first failing stage.
+// The error is a *ProbeStageError naming it, so a slow broker is told apart from missing migrations
+func StagedProber(stages ...ProbeStage) ProberFunc {
+	return func(ctx context.Context) error {
+		for i, stage := range stages {
+			err := observeProbe(ctx, "staged", i+1, func() error { return probeStage(ctx, stage) })
+			if err != nil {
+				return &ProbeStageError{Stage: stage.Name, Err: err}
+			}
+		}
+		return nil
+	}
+}
+
+// probeStage runs the prober of stage, returning once it did or the stage timed out, even if the prober ignores its context
+func probeStage(ctx context.Context, stage ProbeStage) error {
+	if stage.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, stage.Timeout)
+		defer cancel()
+	}
+
+	errChan := make(chan error, 1) // Buffered so the probe goroutine is reclaimed even if nobody receives
+	go func() {
+		errChan <- stage.Prober(ctx)
+	}()
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("stage timed out: %w", context.Cause(ctx))
+		}
+		return context.Cause(ctx)
+	}
+}
+
+// ProbeAttempt describes a single attempt made by one of the prober combinators
+type ProbeAttempt struct {
+	// Kind is the combinator making the attempt, "retrying", "parallel", "any" or "staged", or "observed" for ObservedProber
+	Kind string
+	// Name is the name of the innermost NamedProber the attempt was made in, empty if none. Use it to label metrics
+	Name string
+	// Attempt is the attempt number for retrying probers and the position of the prober or stage for the other combinators, starting at 1
+	Attempt  int
+	Err      error
+	Duration time.Duration
+}
+
+// ProbeObserver is invoked on every attempt of RetryingProber, BackoffProber, ParallelProber, AnyProber and StagedProber
+// and every run of ObservedProber, e.g. to drive a progress bar or to record readiness metrics
+type ProbeObserver func(ProbeAttempt)
+
+type (
+	probeObserverKey struct{}
+	probeNameKey     struct{}
+)
+
+// WithProbeObserver returns a context that makes the prober combinators report their attempts to observer.
+// Because the observer travels with the context, nested combinators are observed without wrapping every prober
+func WithProbeObserver(ctx context.Context, observer ProbeObserver) context.Context {
+	return context.WithValue(ctx, probeObserverKey{}, observer)
+}
+
+// ObservedProber reports every run of prober to observer with Kind "observed", as well as the attempts of the combinators
+// within it, e.g. to feed the outcome and latency of probers reused outside of tests into metrics.
+// Wrap it in a NamedProber to label its runs
+func ObservedProber(prober ProberFunc, observer ProbeObserver) ProberFunc {
+	var runs atomic.Int64
+	return func(ctx context.Context) error {
+		ctx = WithProbeObserver(ctx, observer)
+		return observeProbe(ctx, "observed", int(runs.Add(1)), func() error { return prober(ctx) })
+	}
+}
+
+func observeProbe(ctx context.Context, kind string, attempt int, probe func() error) error {
+	observer, ok := ctx.Value(probeObserverKey{}).(ProbeObserver)
+	if !ok {
+		return probe()
+	}
+
+	start := time.Now()
+	err := probe()
+	observer(ProbeAttempt{Kind: kind, Name: probeName(ctx), Attempt: attempt, Err: err, Duration: time.Since(start)})
+	return err
+}
+
+// probeName is the name of the innermost NamedProber ctx was passed through
+func probeName(ctx context.Context) string {
+	name, _ := ctx.Value(probeNameKey{}).(string)
+	return name
+}
+
 // TestMain is a test entry point that sets up a service.
 // It instructs the manager with the test fixtures and run the prober.
 // Whenever the prober gives green light, the tests are run.
 // Great for acceptance tests, where you want to setup some fixtures (usually mocks) and run the tests.
+// See RunTestMain for more control and to learn why a run failed
 func TestMain(m TestingM, manager *Manager, prober ProberFunc, testFixtures ...Component) int {
+	return RunTestMain(m, manager, TestMainConfig{Prober: prober, Fixtures: testFixtures}).ExitCode
+}
+
+// TestMainContext runs the tests like TestMain, but gives up on them when ctx is done before the prober gave the green light
+func TestMainContext(ctx context.Context, m TestingM, manager *Manager, prober ProberFunc, testFixtures ...Component) int {
+	return RunTestMain(m, manager, TestMainConfig{Context: ctx, Prober: prober, Fixtures: testFixtures}).ExitCode
+}
+
+// TestMainConfig configures RunTestMain
+type TestMainConfig struct {
+	// Context governs the startup: the prober gets it, and the tests are not run when it is done before they start.
+	// Unless FixtureSetupTimeout is set, the fixtures must also be set up by its deadline. Not used once the tests run
+	Context context.Context
+	// Fixtures are added to the manager as components named "test-fixture-<type>",
+	// suffixed with "-2", "-3" and so on for further fixtures of the same type
+	Fixtures []Component
+	// ProbedFixtures are added like Fixtures, after them. Their probers run in parallel once all components are started,
+	// before Prober, so a fixture that never becomes ready is named
+	ProbedFixtures []TestFixture
+	// FixtureSetupTimeout overrides the setup timeout of the manager for the fixtures
+	FixtureSetupTimeout time.Duration
+	// Prober gives the green light for running the tests once all components are started. Nil runs them right away
+	Prober ProberFunc
+	// Logger replaces the logger of the manager, e.g. to silence it or to log through testing
+	Logger *slog.Logger
+	// ExitCode maps the outcome to the exit code, e.g. to tell failing fixtures from failing tests in CI.
+	// Default is the exit code of the tests, SIGUSR1 when the prober failed and the exit code of the manager
+	// when it failed itself
+	ExitCode func(TestMainResult) int
+}
+
+// TestFixture is a fixture of RunTestMain with a readiness prober of its own
+type TestFixture struct {
+	Component Component
+	// Prober tells when the fixture is ready, nil if it is ready once started
+	Prober ProberFunc
+}
+
+// TestMainResult describes how RunTestMain ended
+type TestMainResult struct {
+	// ExitCode is the exit code to pass to os.Exit, see TestMainConfig.ExitCode
+	ExitCode int
+	// TestsRun reports whether the tests were run, TestsExitCode is what running them returned
+	TestsRun      bool
+	TestsExitCode int
+	// ProberErr is why the prober did not give the green light, if it didn't, or the cause of the Context being done
+	ProberErr error
+	// FailedStage names the stage of a StagedProber that failed, if any
+	FailedStage string
+	// NotReadyFixtures names the probed fixtures whose prober failed
+	NotReadyFixtures []string
+	// TeardownErr is the error the manager ended with after the tests ran, e.g. a fixture failing to close. It fails the run
+	TeardownErr error
+	// Result is the result of the manager, telling e.g. which fixture failed to set up
+	Result Result
+}
+
+// RunTestMain is TestMain with a configuration, reporting why a run failed.
+// The tests are run as the lifetime of manager: once its components and the fixtures are started and the prober succeeded
+func RunTestMain(m TestingM, manager *Manager, config TestMainConfig) TestMainResult {
+	var (
+		ctx    = cmp.Or(config.Context, context.Background())
+		names  = fixtureNames(config)
+		result TestMainResult
+	)
+	manager.lifetime = func() int {
+		if ctx.Err() != nil {
+			err := fmt.Errorf("startup context done: %w", context.Cause(ctx))
+			manager.logError(CodeTestProberFailed, "unable to run tests due to the context being done before startup completed", "error", err)
+			result.ProberErr = err
+			return int(proberFailedSignal)
+		}
+		if notReady, err := probeFixtures(ctx, config.ProbedFixtures, names[len(config.Fixtures):]); err != nil {
+			manager.logError(CodeTestProberFailed, "unable to run tests due to fixtures not becoming ready", "error", err, "fixtures", notReady)
+			result.ProberErr, result.NotReadyFixtures = err, notReady
+			return int(proberFailedSignal)
+		}
+		if config.Prober != nil {
+			if err := config.Prober(ctx); err != nil {
+				manager.logError(CodeTestProberFailed, "unable to run tests due to prober failing with error", "error", err)
+				result.ProberErr = err
+				var stageErr *ProbeStageError
+				if errors.As(err, &stageErr) {
+					result.FailedStage = stageErr.Stage
+				}
+				return int(proberFailedSignal)
+			}
+		}
+		result.TestsRun = true
+		result.TestsExitCode = m.Run()
+		return result.TestsExitCode
+	}
+	if config.Logger != nil {
+		manager.logger, manager.logLevel = config.Logger, nil
+	}
+
+	var options []componentOption
+	if deadline, ok := ctx.Deadline(); ok && config.FixtureSetupTimeout <= 0 {
+		config.FixtureSetupTimeout = max(time.Until(deadline), time.Nanosecond)
+	}
+	if config.FixtureSetupTimeout > 0 {
+		options = append(options, WithComponentSetupTimeout(config.FixtureSetupTimeout))
+	}
+	for i, component := range config.Fixtures {
+		manager.Add(names[i], component, options...)
+	}
+	for i, fixture := range config.ProbedFixtures {
+		manager.Add(names[len(config.Fixtures)+i], fixture.Component, options...)
+	}
+
+	result.Result = manager.RunWithResult()
+	if result.TestsRun && result.Result.Err != nil {
+		result.TeardownErr = result.Result.Err
+		manager.logError(CodeTestTeardownFailed, "tests ran, but the manager ended with error", "error", result.TeardownErr)
+	}
+	result.ExitCode = result.Result.ExitCode
+	if config.ExitCode != nil {
+		result.ExitCode = config.ExitCode(result)
+	}
+	return result
+}
+
+// fixtureNames names the Fixtures and then the ProbedFixtures after their type, numbering fixtures of the same type
+// from the second one on, as component names must be unique
+func fixtureNames(config TestMainConfig) []string {
 	var (
-		managerStopped = make(chan int)
-		proberLifetime = func() int {
-			if err := prober(context.Background()); err != nil {
-				manager.logError("unable to run tests due to prober failing with error", "error", err)
-				return int(syscall.SIGUSR1)
+		names = make([]string, 0, len(config.Fixtures)+len(config.ProbedFixtures))
+		seen  = map[string]int{}
+		name  = func(component Component) string {
+			name := fmt.Sprintf("test-fixture-%T", component)
+			seen[name]++
+			if n := seen[name]; n > 1 {
+				return fmt.Sprintf("%s-%d", name, n)
 			}
-			return m.Run()
+			return name
 		}
 	)
-	manager.lifetime = proberLifetime
+	for _, component := range config.Fixtures {
+		names = append(names, name(component))
+	}
+	for _, fixture := range config.ProbedFixtures {
+		names = append(names, name(fixture.Component))
+	}
+	return names
+}
 
-	for _, component := range testFixtures {
-		manager.Add(fmt.Sprintf("test-fixture-%T", component), component)
+// probeFixtures runs the probers of the fixtures in parallel, returning the names of those that failed in the order of the fixtures
+func probeFixtures(ctx context.Context, fixtures []TestFixture, names []string) (notReady []string, err error) {
+	var (
+		errs = make([]error, len(fixtures))
+		wg   sync.WaitGroup
+	)
+	for i, fixture := range fixtures {
+		if fixture.Prober == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fixture.Prober(ctx); err != nil {
+				errs[i] = fmt.Errorf("fixture %q not ready: %w", names[i], err)
+			}
+		}()
 	}
+	wg.Wait()
 
-	go func() {
-		sysSignal := manager.Run()
-		managerStopped <- sysSignal
-	}()
+	for i, err := range errs {
+		if err != nil {
+			notReady = append(notReady, names[i])
+		}
+	}
+	return notReady, errors.Join(errs...)
+}
 
-	return int(<-managerStopped)
+// ManagerReadyProber succeeds once every component of the manager has been started and none of them failed.
+// Components whose Start returned without an error (e.g. Setup or Closer helpers) count as ready.
+// Combine it with RetryingProber to wait for the whole service to be up
+func ManagerReadyProber(manager *Manager) ProberFunc {
+	return func(ctx context.Context) error {
+		var notReady []string
+		for info := range manager.Components() {
+			if info.State != StateRunning && info.State != StateStopped {
+				notReady = append(notReady, fmt.Sprintf("%s (%s)", info.Name, info.State))
+			}
+		}
+		if len(notReady) > 0 {
+			return fmt.Errorf("components not ready: %s", strings.Join(notReady, ", "))
+		}
+		return nil
+	}
+}
+
+// ProbeError is returned by RetryingProber and BackoffProber when they give up, reporting the attempts they made
+type ProbeError struct {
+	// Err is why the prober gave up, context.DeadlineExceeded once it timed out
+	Err      error
+	Attempts []ProbeAttempt
+	// Elapsed is how long the prober tried
+	Elapsed time.Duration
+}
+
+// LastErr is the error of the last attempt, nil if there was none
+func (e *ProbeError) LastErr() error {
+	if len(e.Attempts) == 0 {
+		return nil
+	}
+	return e.Attempts[len(e.Attempts)-1].Err
+}
+
+func (e *ProbeError) Error() string {
+	reason := "retrying prober failed"
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		reason = "prober timed out"
+	}
+	msg := fmt.Sprintf("%s: %v (attempts: %d, elapsed: %s", reason, e.Err, len(e.Attempts), e.Elapsed.Round(time.Millisecond))
+	if lastErr := e.LastErr(); lastErr != nil {
+		msg += fmt.Sprintf(", last error: %v", lastErr)
+	}
+	return msg + ")"
+}
+
+// Unwrap returns why the prober gave up and the error of the last attempt
+func (e *ProbeError) Unwrap() []error {
+	if lastErr := e.LastErr(); lastErr != nil {
+		return []error{e.Err, lastErr}
+	}
+	return []error{e.Err}
+}
+
+// retryReport records the attempts of a retrying prober, for the ProbeError once it gives up
+type retryReport struct {
+	began    time.Time
+	attempts []ProbeAttempt
+}
+
+func (r *retryReport) probe(ctx context.Context, probe func() error) error {
+	var (
+		attempt = len(r.attempts) + 1
+		start   = time.Now()
+		err     = observeProbe(ctx, "retrying", attempt, probe)
+	)
+	r.attempts = append(r.attempts, ProbeAttempt{Kind: "retrying", Name: probeName(ctx), Attempt: attempt, Err: err, Duration: time.Since(start)})
+	return err
+}
+
+func (r *retryReport) gaveUp(err error) *ProbeError {
+	return &ProbeError{Err: err, Attempts: r.attempts, Elapsed: time.Since(r.began)}
 }
 
 func RetryingProber(retryDelay time.Duration, timeout time.Duration, prober ProberFunc) ProberFunc {
@@ -57,6 +457,7 @@ func RetryingProber(retryDelay time.Duration, timeout time.Duration, prober Prob
 		var (
 			newCtx, cancel = context.WithTimeout(ctx, timeout)
 			t              = time.NewTicker(retryDelay)
+			report         = &retryReport{began: time.Now()}
 		)
 		defer cancel()
 		defer t.Stop()
@@ -64,15 +465,12 @@ func RetryingProber(retryDelay time.Duration, timeout time.Duration, prober Prob
 		for {
 			select {
 			case <-newCtx.Done():
-				if newCtx.Err() == context.DeadlineExceeded {
-					return fmt.Errorf("prober timed out: %w", newCtx.Err())
-				}
-				return fmt.Errorf("retrying prober failed: %w", newCtx.Err())
+				return report.gaveUp(newCtx.Err())
 			case tick := <-t.C:
 				attemptCtx, attemptCancel := context.WithTimeout(newCtx, time.Until(tick.Add(retryDelay)))
 				defer attemptCancel()
 
-				if err := prober(attemptCtx); err != nil {
+				if err := report.probe(ctx, func() error { return prober(attemptCtx) }); err != nil {
 					continue // retry
 				}
 				return nil // success
@@ -81,19 +479,73 @@ func RetryingProber(retryDelay time.Duration, timeout time.Duration, prober Prob
 	}
 }
 
+// BackoffProber runs prober right away and retries it until it succeeds, waiting for backoff before every retry,
+// e.g. an ExponentialBackoff with jitter to go easy on a slow dependency early on without waiting long once it is up.
+// Gives up after timeout like RetryingProber
+func BackoffProber(backoff Backoff, timeout time.Duration, prober ProberFunc) ProberFunc {
+	return func(ctx context.Context) error {
+		newCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		report := &retryReport{began: time.Now()}
+		for {
+			if err := report.probe(ctx, func() error { return prober(newCtx) }); err == nil {
+				return nil
+			}
+
+			t := time.NewTimer(backoff(len(report.attempts)))
+			select {
+			case <-newCtx.Done():
+				t.Stop()
+				return report.gaveUp(newCtx.Err())
+			case <-t.C:
+			}
+		}
+	}
+}
+
+// ParallelProber runs the probers in parallel and succeeds once all of them succeeded.
+// It fails fast: as soon as one prober fails, the others are cancelled, see ParallelProberWaitAll
 func ParallelProber(probers ...ProberFunc) ProberFunc {
+	return parallelProber(true, probers)
+}
+
+// ParallelProberWaitAll runs the probers in parallel like ParallelProber, but lets every prober finish instead of
+// cancelling the others once one fails, e.g. when they are retrying probers that would still succeed.
+// It fails with the errors of all failed probers
+func ParallelProberWaitAll(probers ...ProberFunc) ProberFunc {
+	return parallelProber(false, probers)
+}
+
+func parallelProber(failFast bool, probers []ProberFunc) ProberFunc {
 	return func(ctx context.Context) error {
+		// Every probe is scoped to this call, so probes are cancelled as soon as we return no matter the outcome
+		scopedCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
 		var (
-			errGroup, newCtx = errgroup.WithContext(ctx)
-			errChan          = make(chan error)
+			errGroup, probeCtx = &errgroup.Group{}, scopedCtx
+			errs               = make([]error, len(probers))
+			errChan            = make(chan error, 1) // Buffered so the wait goroutine is reclaimed even if nobody receives
 		)
-		for _, probe := range probers {
+		if failFast {
+			errGroup, probeCtx = errgroup.WithContext(scopedCtx)
+		}
+		for i, probe := range probers {
 			errGroup.Go(func() error {
-				return probe(newCtx)
+				errs[i] = observeProbe(ctx, "parallel", i+1, func() error { return probe(probeCtx) })
+				if failFast {
+					return errs[i]
+				}
+				return nil
 			})
 		}
 		go func() {
-			errChan <- errGroup.Wait()
+			err := errGroup.Wait()
+			if !failFast {
+				err = errors.Join(errs...)
+			}
+			errChan <- err
 		}()
 		select {
 		case err := <-errChan:
@@ -103,9 +555,9 @@ func ParallelProber(probers ...ProberFunc) ProberFunc {
 			return nil
 		case <-ctx.Done():
 			if ctx.Err() == context.DeadlineExceeded {
-				return fmt.Errorf("parallel prober timed out: %w", newCtx.Err())
+				return fmt.Errorf("parallel prober timed out: %w", context.Cause(ctx))
 			}
-			return fmt.Errorf("parallel prober failed: %w", newCtx.Err())
+			return fmt.Errorf("parallel prober failed: %w", context.Cause(ctx))
 		}
 	}
 }
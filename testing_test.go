@@ -1,7 +1,11 @@
 package unixcycle_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"sync"
 	"sync/atomic"
 	"syscall"
@@ -65,6 +69,35 @@ func TestTesting(t *testing.T) {
 			assert.Equal(t, maxAttempts/2, int(prober.calls.Load()))
 		})
 
+		t.Run("should report the attempts when giving up", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				lastErr = errors.New("connection refused")
+				sut     = unixcycle.RetryingProber(10*time.Millisecond, 100*time.Millisecond, func(ctx context.Context) error {
+					return lastErr
+				})
+			)
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			var probeErr *unixcycle.ProbeError
+			require.ErrorAs(t, err, &probeErr)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.ErrorIs(t, err, lastErr)
+			assert.ErrorIs(t, probeErr.LastErr(), lastErr)
+			assert.NotEmpty(t, probeErr.Attempts)
+			for i, attempt := range probeErr.Attempts {
+				assert.Equal(t, i+1, attempt.Attempt)
+				assert.Equal(t, lastErr, attempt.Err)
+			}
+			assert.GreaterOrEqual(t, probeErr.Elapsed, 100*time.Millisecond)
+			assert.ErrorContains(t, err, fmt.Sprintf("prober timed out: context deadline exceeded (attempts: %d, elapsed: ", len(probeErr.Attempts)))
+			assert.ErrorContains(t, err, "last error: connection refused)")
+		})
+
 		t.Run("should return nil if prober succeeds", func(t *testing.T) {
 			t.Parallel()
 			// Arrange
@@ -83,6 +116,91 @@ func TestTesting(t *testing.T) {
 		})
 	})
 
+	t.Run("BackoffProber", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should probe right away and wait for the backoff between attempts", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				mu      sync.Mutex
+				waited  []int
+				backoff = func(attempt int) time.Duration {
+					mu.Lock()
+					defer mu.Unlock()
+					waited = append(waited, attempt)
+					return time.Millisecond
+				}
+				prober = &simpleMockProber{successAfterCalls: 3}
+				sut    = unixcycle.BackoffProber(backoff, timeout, prober.Probe)
+			)
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, 3, int(prober.calls.Load()))
+			assert.Equal(t, []int{1, 2}, waited)
+		})
+
+		t.Run("should grow the delay between attempts with an exponential backoff", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				backoff = unixcycle.ExponentialBackoff(10*time.Millisecond, 2).WithCap(40 * time.Millisecond)
+				prober  = &simpleMockProber{successAfterCalls: maxAttempts * 100}
+				sut     = unixcycle.BackoffProber(backoff, 200*time.Millisecond, prober.Probe)
+			)
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			assert.ErrorContains(t, err, "prober timed out")
+			// 10 + 20 + 40 + 40 + 40 + 40 ms, so about 7 attempts within 200ms rather than 20 with a constant 10ms
+			assert.Less(t, int(prober.calls.Load()), 10)
+		})
+
+		t.Run("should report the attempts when giving up", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			sut := unixcycle.BackoffProber(unixcycle.ConstantBackoff(time.Second), 50*time.Millisecond, func(ctx context.Context) error {
+				return assert.AnError
+			})
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			var probeErr *unixcycle.ProbeError
+			require.ErrorAs(t, err, &probeErr)
+			require.Len(t, probeErr.Attempts, 1)
+			assert.Equal(t, "retrying", probeErr.Attempts[0].Kind)
+			assert.ErrorIs(t, probeErr.LastErr(), assert.AnError)
+			assert.ErrorContains(t, err, "prober timed out: context deadline exceeded (attempts: 1, elapsed: ")
+		})
+
+		t.Run("should return error if context is cancelled", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				ctx, cancel = context.WithCancel(context.Background())
+				prober      = &simpleMockProber{successAfterCalls: maxAttempts + 1}
+				sut         = unixcycle.BackoffProber(unixcycle.ConstantBackoff(retryDelay), timeout, prober.Probe)
+			)
+			time.AfterFunc(retryDelay/2, cancel)
+
+			// Act
+			err := sut(ctx)
+
+			// Assert
+			assert.ErrorContains(t, err, "retrying prober failed")
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.Equal(t, 1, int(prober.calls.Load()))
+		})
+	})
+
 	t.Run("ParallelProber", func(t *testing.T) {
 		t.Parallel()
 
@@ -126,6 +244,36 @@ func TestTesting(t *testing.T) {
 			assert.Equal(t, 0, int(prober2.calls.Load())) // prober2 should not have been called due to timeout
 		})
 
+		t.Run("should return the cause of the outer context and cancel the probes", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				ctx, cancel = context.WithCancelCause(context.Background())
+				cause       = errors.New("shutting down")
+				cancelled   = make(chan struct{})
+				blocking    = func(ctx context.Context) error {
+					<-ctx.Done()
+					close(cancelled)
+					return ctx.Err()
+				}
+				sut = unixcycle.ParallelProber(blocking)
+			)
+			go cancel(cause)
+
+			// Act
+			err := sut(ctx)
+
+			// Assert
+			require.Error(t, err)
+			assert.ErrorContains(t, err, "parallel prober failed")
+			assert.ErrorIs(t, err, cause)
+			select {
+			case <-cancelled:
+			case <-time.After(time.Second):
+				t.Error("probe should have been cancelled")
+			}
+		})
+
 		t.Run("should return nil if all probers succeed", func(t *testing.T) {
 			t.Parallel()
 			// Arrange
@@ -220,6 +368,414 @@ func TestTesting(t *testing.T) {
 		})
 	})
 
+	t.Run("WithProbeObserver", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should report every attempt of nested probers", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				mu       sync.Mutex
+				attempts []unixcycle.ProbeAttempt
+				observer = func(a unixcycle.ProbeAttempt) {
+					mu.Lock()
+					defer mu.Unlock()
+					attempts = append(attempts, a)
+				}
+				prober = &simpleMockProber{successAfterCalls: 2}
+				sut    = unixcycle.ParallelProber(unixcycle.RetryingProber(10*time.Millisecond, timeout, prober.Probe))
+				ctx    = unixcycle.WithProbeObserver(context.Background(), observer)
+			)
+
+			// Act
+			err := sut(ctx)
+
+			// Assert
+			require.NoError(t, err)
+			require.Len(t, attempts, 3)
+			assert.Equal(t, "retrying", attempts[0].Kind)
+			assert.Equal(t, 1, attempts[0].Attempt)
+			assert.Error(t, attempts[0].Err)
+			assert.Equal(t, "retrying", attempts[1].Kind)
+			assert.Equal(t, 2, attempts[1].Attempt)
+			assert.NoError(t, attempts[1].Err)
+			assert.Equal(t, "parallel", attempts[2].Kind)
+			assert.Equal(t, 1, attempts[2].Attempt)
+			assert.NoError(t, attempts[2].Err)
+		})
+	})
+
+	t.Run("ManagerReadyProber", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should only succeed once all components are started", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				setupDone = make(chan struct{})
+				shutdown  = make(chan int)
+				manager   = unixcycle.NewManager(unixcycle.WithLifetime(func() int { return <-shutdown })).
+						Add("slow setup", unixcycle.Setup(func() error { <-setupDone; return nil })).
+						Add("worker", unixcycle.Starter(func() error { select {} }))
+				sut  = unixcycle.ManagerReadyProber(manager)
+				done = make(chan int)
+			)
+			go func() { done <- manager.Run() }()
+
+			// Act
+			errBeforeStart := sut(context.Background())
+			close(setupDone)
+			errAfterStart := unixcycle.RetryingProber(10*time.Millisecond, time.Second, sut)(context.Background())
+
+			// Assert
+			assert.ErrorContains(t, errBeforeStart, "worker (registered)")
+			assert.NoError(t, errAfterStart)
+			shutdown <- 0
+			<-done
+		})
+	})
+
+	t.Run("ParallelProberWaitAll", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should let the other probers finish when one fails", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				failing  = &simpleMockProber{successAfterCalls: maxAttempts + 1}
+				retrying = &simpleMockProber{successAfterCalls: 3}
+				sut      = unixcycle.ParallelProberWaitAll(
+					failing.Probe,
+					unixcycle.RetryingProber(10*time.Millisecond, time.Second, retrying.Probe),
+				)
+			)
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			require.Error(t, err)
+			assert.ErrorContains(t, err, "parallel prober errored")
+			assert.Equal(t, 3, int(retrying.calls.Load()), "the retrying prober should not have been cancelled")
+		})
+
+		t.Run("should fail with the errors of all failed probers", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				refused = errors.New("connection refused")
+				sut     = unixcycle.ParallelProberWaitAll(
+					func(ctx context.Context) error { return refused },
+					func(ctx context.Context) error { return nil },
+					func(ctx context.Context) error { return assert.AnError },
+				)
+			)
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			assert.ErrorIs(t, err, refused)
+			assert.ErrorIs(t, err, assert.AnError)
+		})
+
+		t.Run("should succeed once all probers succeeded", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				prober1 = &simpleMockProber{}
+				prober2 = &simpleMockProber{}
+				sut     = unixcycle.ParallelProberWaitAll(prober1.Probe, prober2.Probe)
+			)
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 1, int(prober1.calls.Load()))
+			assert.Equal(t, 1, int(prober2.calls.Load()))
+		})
+	})
+
+	t.Run("NamedProber", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should prefix the error with the name", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				refused = errors.New("connection refused")
+				sut     = unixcycle.ParallelProber(
+					unixcycle.NamedProber("postgres", func(ctx context.Context) error { return nil }),
+					unixcycle.NamedProber("kafka", func(ctx context.Context) error { return refused }),
+				)
+			)
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			assert.EqualError(t, err, "parallel prober errored: kafka: connection refused")
+			assert.ErrorIs(t, err, refused)
+		})
+
+		t.Run("should succeed when the prober succeeds", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			sut := unixcycle.NamedProber("kafka", func(ctx context.Context) error { return nil })
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			assert.NoError(t, err)
+		})
+	})
+
+	t.Run("ObservedProber", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should report every run and the attempts within it", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				mu       sync.Mutex
+				attempts []unixcycle.ProbeAttempt
+				observer = func(attempt unixcycle.ProbeAttempt) {
+					mu.Lock()
+					defer mu.Unlock()
+					attempts = append(attempts, attempt)
+				}
+				prober = &simpleMockProber{successAfterCalls: 2}
+				sut    = unixcycle.ObservedProber(unixcycle.RetryingProber(10*time.Millisecond, time.Second, prober.Probe), observer)
+			)
+
+			// Act
+			err := sut(context.Background())
+			secondErr := sut(context.Background())
+
+			// Assert
+			require.NoError(t, err)
+			require.NoError(t, secondErr)
+			require.Len(t, attempts, 5)
+			assert.Equal(t, []string{"retrying", "retrying", "observed", "retrying", "observed"},
+				[]string{attempts[0].Kind, attempts[1].Kind, attempts[2].Kind, attempts[3].Kind, attempts[4].Kind})
+			assert.Error(t, attempts[0].Err)
+			assert.NoError(t, attempts[2].Err)
+			assert.Equal(t, 1, attempts[2].Attempt)
+			assert.Equal(t, 2, attempts[4].Attempt)
+			assert.GreaterOrEqual(t, attempts[2].Duration, attempts[0].Duration+attempts[1].Duration)
+		})
+
+		t.Run("should label the attempts with the name of the innermost named prober", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				mu       sync.Mutex
+				names    = map[string][]string{}
+				observer = func(attempt unixcycle.ProbeAttempt) {
+					mu.Lock()
+					defer mu.Unlock()
+					names[attempt.Kind] = append(names[attempt.Kind], attempt.Name)
+				}
+				succeed = func(ctx context.Context) error { return nil }
+				sut     = unixcycle.NamedProber("canary", unixcycle.ObservedProber(unixcycle.ParallelProber(
+					unixcycle.NamedProber("kafka", unixcycle.RetryingProber(time.Millisecond, time.Second, succeed)),
+					succeed,
+				), observer))
+			)
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, []string{"canary"}, names["observed"])
+			assert.ElementsMatch(t, []string{"canary", "canary"}, names["parallel"])
+			assert.Equal(t, []string{"kafka"}, names["retrying"])
+		})
+	})
+
+	t.Run("AnyProber", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should succeed as soon as any prober succeeds and cancel the others", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				cancelled = make(chan struct{})
+				ipv4      = func(ctx context.Context) error {
+					<-ctx.Done()
+					close(cancelled)
+					return ctx.Err()
+				}
+				ipv6 = func(ctx context.Context) error { return nil }
+				sut  = unixcycle.AnyProber(ipv4, ipv6)
+			)
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			require.NoError(t, err)
+			select {
+			case <-cancelled:
+			case <-time.After(time.Second):
+				t.Fatal("the other prober was not cancelled")
+			}
+		})
+
+		t.Run("should fail with every error once all probers failed", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				refused = errors.New("connection refused")
+				sut     = unixcycle.AnyProber(
+					func(ctx context.Context) error { return refused },
+					func(ctx context.Context) error { return assert.AnError },
+				)
+			)
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			assert.ErrorContains(t, err, "all probers of any prober failed")
+			assert.ErrorIs(t, err, refused)
+			assert.ErrorIs(t, err, assert.AnError)
+		})
+
+		t.Run("should return the cause of the outer context", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				ctx, cancel = context.WithCancelCause(context.Background())
+				cause       = errors.New("shutting down")
+				blocking    = func(ctx context.Context) error {
+					<-ctx.Done()
+					return context.Cause(ctx)
+				}
+				sut = unixcycle.AnyProber(blocking, blocking)
+			)
+			go cancel(cause)
+
+			// Act
+			err := sut(ctx)
+
+			// Assert
+			assert.ErrorIs(t, err, cause)
+		})
+
+		t.Run("should fail without probers", func(t *testing.T) {
+			t.Parallel()
+			// Act
+			err := unixcycle.AnyProber()(context.Background())
+
+			// Assert
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("StagedProber", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should run the stages in order", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				mu    sync.Mutex
+				ran   []string
+				stage = func(name string) unixcycle.ProbeStage {
+					return unixcycle.Stage(name, timeout, func(ctx context.Context) error {
+						mu.Lock()
+						defer mu.Unlock()
+						ran = append(ran, name)
+						return nil
+					})
+				}
+				sut = unixcycle.StagedProber(stage("broker reachable"), stage("migrations applied"), stage("api healthy"))
+			)
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, []string{"broker reachable", "migrations applied", "api healthy"}, ran)
+		})
+
+		t.Run("should name the failing stage and skip the later ones", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				later = &simpleMockProber{}
+				sut   = unixcycle.StagedProber(
+					unixcycle.Stage("broker reachable", timeout, func(ctx context.Context) error { return nil }),
+					unixcycle.Stage("migrations applied", timeout, func(ctx context.Context) error { return assert.AnError }),
+					unixcycle.Stage("api healthy", timeout, later.Probe),
+				)
+			)
+
+			// Act
+			err := sut(context.Background())
+
+			// Assert
+			var stageErr *unixcycle.ProbeStageError
+			require.ErrorAs(t, err, &stageErr)
+			assert.Equal(t, "migrations applied", stageErr.Stage)
+			assert.ErrorIs(t, err, assert.AnError)
+			assert.ErrorContains(t, err, `probe stage "migrations applied" failed`)
+			assert.Zero(t, later.calls.Load())
+		})
+
+		t.Run("should time out a stage on its own, even if its prober ignores the context", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				stuck = &simpleMockProber{workLoad: timeout}
+				sut   = unixcycle.StagedProber(unixcycle.Stage("api healthy", retryDelay, stuck.Probe))
+			)
+
+			// Act
+			began := time.Now()
+			err := sut(context.Background())
+
+			// Assert
+			var stageErr *unixcycle.ProbeStageError
+			require.ErrorAs(t, err, &stageErr)
+			assert.Equal(t, "api healthy", stageErr.Stage)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.ErrorContains(t, err, "stage timed out")
+			assert.Less(t, time.Since(began), timeout)
+		})
+
+		t.Run("should report every stage to the probe observer", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				attempts []unixcycle.ProbeAttempt
+				ctx      = unixcycle.WithProbeObserver(context.Background(), func(attempt unixcycle.ProbeAttempt) {
+					attempts = append(attempts, attempt)
+				})
+				sut = unixcycle.StagedProber(
+					unixcycle.Stage("first", 0, func(ctx context.Context) error { return nil }),
+					unixcycle.Stage("second", 0, func(ctx context.Context) error { return nil }),
+				)
+			)
+
+			// Act
+			err := sut(ctx)
+
+			// Assert
+			require.NoError(t, err)
+			require.Len(t, attempts, 2)
+			assert.Equal(t, "staged", attempts[0].Kind)
+			assert.Equal(t, 1, attempts[0].Attempt)
+			assert.Equal(t, 2, attempts[1].Attempt)
+		})
+	})
+
 	t.Run("TestMain", func(t *testing.T) {
 		type dependencies struct {
 			testingM     *TestingMMock
@@ -333,6 +889,304 @@ func TestTesting(t *testing.T) {
 			assert.Len(t, deps.prober.ProbeCalls(), 1)
 		})
 	})
+
+	t.Run("RunTestMain", func(t *testing.T) {
+		t.Parallel()
+
+		newTestingM := func(exitCode int) *TestingMMock {
+			return &TestingMMock{RunFunc: func() int { return exitCode }}
+		}
+
+		t.Run("should report the exit code of the tests", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				testingM    = newTestingM(3)
+				testFixture = &componentMock{}
+			)
+
+			// Act
+			result := unixcycle.RunTestMain(testingM, unixcycle.NewManager(), unixcycle.TestMainConfig{
+				Fixtures: []unixcycle.Component{testFixture},
+			})
+
+			// Assert
+			assert.Equal(t, 3, result.ExitCode)
+			assert.True(t, result.TestsRun)
+			assert.Equal(t, 3, result.TestsExitCode)
+			assert.NoError(t, result.ProberErr)
+			assert.Equal(t, 1, testFixture.getCloseCalls())
+		})
+
+		t.Run("should report why the prober failed", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testingM := newTestingM(0)
+
+			// Act
+			result := unixcycle.RunTestMain(testingM, unixcycle.NewManager(), unixcycle.TestMainConfig{
+				Prober: func(ctx context.Context) error { return assert.AnError },
+			})
+
+			// Assert
+			assert.Equal(t, int(syscall.SIGUSR1), result.ExitCode)
+			assert.False(t, result.TestsRun)
+			assert.ErrorIs(t, result.ProberErr, assert.AnError)
+			assert.Empty(t, testingM.RunCalls())
+		})
+
+		t.Run("should report which fixture failed to set up in time", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				testingM    = newTestingM(0)
+				testFixture = &componentMock{setupFunc: func() error {
+					time.Sleep(time.Second)
+					return nil
+				}}
+			)
+
+			// Act
+			result := unixcycle.RunTestMain(testingM, unixcycle.NewManager(), unixcycle.TestMainConfig{
+				Fixtures:            []unixcycle.Component{testFixture},
+				FixtureSetupTimeout: 50 * time.Millisecond,
+			})
+
+			// Assert
+			assert.False(t, result.TestsRun)
+			assert.Equal(t, int(syscall.SIGALRM), result.ExitCode)
+			assert.ErrorIs(t, result.Result.Err, unixcycle.ErrSetupTimeout)
+			assert.Equal(t, unixcycle.PhaseSetup, result.Result.FailedPhase)
+			assert.Contains(t, result.Result.ComponentErrors, "test-fixture-*unixcycle_test.componentMock")
+		})
+
+		t.Run("should run the tests with several fixtures of the same type", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				testingM = newTestingM(0)
+				closed   atomic.Int32
+				fixture  = func() unixcycle.Component {
+					return unixcycle.Closer(func() error { closed.Add(1); return nil })
+				}
+			)
+
+			// Act
+			result := unixcycle.RunTestMain(testingM, unixcycle.NewManager(), unixcycle.TestMainConfig{
+				Fixtures: []unixcycle.Component{fixture(), fixture()},
+			})
+
+			// Assert
+			assert.True(t, result.TestsRun)
+			assert.Equal(t, 0, result.ExitCode)
+			assert.EqualValues(t, 2, closed.Load())
+		})
+
+		t.Run("should log to the configured logger", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				testingM = newTestingM(0)
+				output   = &bytes.Buffer{}
+			)
+
+			// Act
+			unixcycle.RunTestMain(testingM, unixcycle.NewManager(), unixcycle.TestMainConfig{
+				Prober: func(ctx context.Context) error { return assert.AnError },
+				Logger: slog.New(slog.NewTextHandler(output, nil)),
+			})
+
+			// Assert
+			assert.Contains(t, output.String(), string(unixcycle.CodeTestProberFailed))
+		})
+
+		t.Run("should give the prober the context", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				testingM    = newTestingM(0)
+				ctx, cancel = context.WithCancelCause(context.Background())
+				cause       = errors.New("giving up")
+			)
+			defer cancel(nil)
+
+			// Act
+			result := unixcycle.RunTestMain(testingM, unixcycle.NewManager(), unixcycle.TestMainConfig{
+				Context: ctx,
+				Prober: func(ctx context.Context) error {
+					cancel(cause)
+					<-ctx.Done()
+					return context.Cause(ctx)
+				},
+			})
+
+			// Assert
+			assert.False(t, result.TestsRun)
+			assert.ErrorIs(t, result.ProberErr, cause)
+			assert.Equal(t, int(syscall.SIGUSR1), result.ExitCode)
+		})
+
+		t.Run("should not run the tests when the context is done before the startup completed", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				testingM    = newTestingM(0)
+				ctx, cancel = context.WithCancel(context.Background())
+				testFixture = &componentMock{setupFunc: func() error {
+					cancel()
+					return nil
+				}}
+			)
+
+			// Act
+			result := unixcycle.TestMainContext(ctx, testingM, unixcycle.NewManager(), nil, testFixture)
+
+			// Assert
+			assert.Equal(t, int(syscall.SIGUSR1), result)
+			assert.Empty(t, testingM.RunCalls())
+		})
+
+		t.Run("should time out setting up the fixtures at the deadline of the context", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				testingM    = newTestingM(0)
+				ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+				testFixture = &componentMock{setupFunc: func() error {
+					time.Sleep(time.Second)
+					return nil
+				}}
+			)
+			defer cancel()
+
+			// Act
+			result := unixcycle.RunTestMain(testingM, unixcycle.NewManager(), unixcycle.TestMainConfig{
+				Context:  ctx,
+				Fixtures: []unixcycle.Component{testFixture},
+			})
+
+			// Assert
+			assert.False(t, result.TestsRun)
+			assert.ErrorIs(t, result.Result.Err, unixcycle.ErrSetupTimeout)
+		})
+
+		t.Run("should fail the run when a fixture fails to close after the tests", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				testingM    = newTestingM(0)
+				testFixture = &componentMock{closeFunc: func() error { return assert.AnError }}
+			)
+
+			// Act
+			result := unixcycle.RunTestMain(testingM, unixcycle.NewManager(), unixcycle.TestMainConfig{
+				Fixtures: []unixcycle.Component{testFixture},
+			})
+
+			// Assert
+			assert.True(t, result.TestsRun)
+			assert.Equal(t, 0, result.TestsExitCode)
+			assert.ErrorIs(t, result.TeardownErr, assert.AnError)
+			assert.Equal(t, unixcycle.PhaseClose, result.Result.FailedPhase)
+			assert.NotZero(t, result.ExitCode)
+		})
+
+		t.Run("should name the failing stage of a staged prober", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testingM := newTestingM(0)
+
+			// Act
+			result := unixcycle.RunTestMain(testingM, unixcycle.NewManager(), unixcycle.TestMainConfig{
+				Prober: unixcycle.StagedProber(
+					unixcycle.Stage("broker reachable", time.Second, func(ctx context.Context) error { return assert.AnError }),
+				),
+			})
+
+			// Assert
+			assert.False(t, result.TestsRun)
+			assert.Equal(t, "broker reachable", result.FailedStage)
+			assert.ErrorIs(t, result.ProberErr, assert.AnError)
+		})
+
+		t.Run("should run the tests once every probed fixture is ready", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				testingM = newTestingM(0)
+				db       = &componentMock{}
+				broker   = &otherComponentMock{}
+				ready    = func(c interface{ getStartCalls() int }) unixcycle.ProberFunc {
+					return unixcycle.RetryingProber(10*time.Millisecond, 2*time.Second, func(ctx context.Context) error {
+						if c.getStartCalls() != 1 {
+							return assert.AnError
+						}
+						return nil
+					})
+				}
+			)
+
+			// Act
+			result := unixcycle.RunTestMain(testingM, unixcycle.NewManager(), unixcycle.TestMainConfig{
+				ProbedFixtures: []unixcycle.TestFixture{
+					{Component: db, Prober: ready(db)},
+					{Component: broker, Prober: ready(broker)},
+				},
+			})
+
+			// Assert
+			assert.True(t, result.TestsRun)
+			assert.Empty(t, result.NotReadyFixtures)
+			assert.Equal(t, 1, db.getCloseCalls())
+			assert.Equal(t, 1, broker.getCloseCalls())
+		})
+
+		t.Run("should name the fixtures that never became ready", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			var (
+				testingM = newTestingM(0)
+				prober   = &ProberMock{ProbeFunc: func(ctx context.Context) error { return nil }}
+			)
+
+			// Act
+			result := unixcycle.RunTestMain(testingM, unixcycle.NewManager(), unixcycle.TestMainConfig{
+				ProbedFixtures: []unixcycle.TestFixture{
+					{Component: &componentMock{}, Prober: func(ctx context.Context) error { return nil }},
+					{Component: &otherComponentMock{}, Prober: func(ctx context.Context) error { return assert.AnError }},
+				},
+				Prober: prober.Probe,
+			})
+
+			// Assert
+			assert.False(t, result.TestsRun)
+			assert.Equal(t, []string{"test-fixture-*unixcycle_test.otherComponentMock"}, result.NotReadyFixtures)
+			assert.ErrorIs(t, result.ProberErr, assert.AnError)
+			assert.ErrorContains(t, result.ProberErr, `fixture "test-fixture-*unixcycle_test.otherComponentMock" not ready`)
+			assert.Empty(t, prober.ProbeCalls())
+			assert.Equal(t, int(syscall.SIGUSR1), result.ExitCode)
+		})
+
+		t.Run("should map the outcome to a custom exit code", func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			testingM := newTestingM(0)
+
+			// Act
+			result := unixcycle.RunTestMain(testingM, unixcycle.NewManager(), unixcycle.TestMainConfig{
+				Prober: func(ctx context.Context) error { return assert.AnError },
+				ExitCode: func(result unixcycle.TestMainResult) int {
+					if result.ProberErr != nil {
+						return 42
+					}
+					return result.TestsExitCode
+				},
+			})
+
+			// Assert
+			assert.Equal(t, 42, result.ExitCode)
+		})
+	})
 }
 
 type simpleMockProber struct {
@@ -351,6 +1205,11 @@ func (s *simpleMockProber) Probe(ctx context.Context) error {
 	return nil
 }
 
+// otherComponentMock is a componentMock of another type, so it is named differently as a fixture
+type otherComponentMock struct {
+	componentMock
+}
+
 type componentMock struct {
 	mu        sync.Mutex
 	setupFunc func() error
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package unixcycle
+
+import "syscall"
+
+// proberFailedSignal is returned from TestMain when the prober fails
+const proberFailedSignal = syscall.SIGUSR1
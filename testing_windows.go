@@ -0,0 +1,9 @@
+//go:build windows
+
+package unixcycle
+
+import "syscall"
+
+// proberFailedSignal is returned from TestMain when the prober fails.
+// Windows has no SIGUSR1, so its value on linux is used
+const proberFailedSignal = syscall.Signal(0xa)
@@ -0,0 +1,81 @@
+package unixcycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+var errTimeout = errors.New("function did not complete within the given timeout")
+
+// timeoutError is returned by funcOrTimeout when the function did not complete in time.
+// Go can't stop a goroutine, so the function keeps running and late receives its error once it returns
+type timeoutError struct {
+	late <-chan error
+}
+
+func (e *timeoutError) Error() string {
+	return errTimeout.Error()
+}
+
+func (e *timeoutError) Is(target error) bool {
+	return target == errTimeout
+}
+
+// funcOrTimeout runs f in its own goroutine and waits for it up to timeout.
+// On timeout it returns a *timeoutError, which still tells when f returns, see trackTimedOut
+func funcOrTimeout(f func() error, timeout time.Duration) error {
+	errs := make(chan error, 1) // Buffered, so f can return after nobody waits for it anymore
+	go func() {
+		errs <- f()
+	}()
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-t.C:
+		return &timeoutError{late: errs}
+	}
+}
+
+// contextFuncOrTimeout is like funcOrTimeout, but also hands f a context that expires shortly before the timeout,
+// so f can stop its work instead of running on after timing out. The context expires a tenth of the timeout
+// (at most a second) early, so f returning once it is done isn't mistaken for a timeout
+func contextFuncOrTimeout(f func(ctx context.Context) error, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout-min(timeout/10, time.Second))
+	defer cancel()
+
+	return funcOrTimeout(func() error { return f(ctx) }, timeout)
+}
+
+// trackTimedOut counts an operation of a component that timed out but is still running, see ResourceUsage.TimedOut,
+// and logs once it eventually returns. Does nothing if err is not a timeout of funcOrTimeout
+func (m *Manager) trackTimedOut(s namedComponent, operation string, err error) {
+	var timeoutErr *timeoutError
+	if !errors.As(err, &timeoutErr) {
+		return
+	}
+
+	m.timedOut.Add(1)
+	timedOutAt := time.Now()
+	go func() {
+		lateErr := <-timeoutErr.late
+		m.timedOut.Add(-1)
+		m.logInfo(CodeTimedOutReturned,
+			fmt.Sprintf("%s of component %q returned %s after timing out: %v", operation, s.name, time.Since(timedOutAt).Round(time.Millisecond), lateErr),
+			slog.String("component_name", s.name), slog.String("operation", operation))
+	}()
+}
+
+// logTimedOut reports the operations still running after timing out once the manager is done,
+// as they may keep holding resources or write after the process considers the component closed
+func (m *Manager) logTimedOut() {
+	if n := m.timedOut.Load(); n > 0 {
+		m.logError(CodeTimedOutRunning, fmt.Sprintf("%d operations of components are still running after timing out", n), slog.Int64("operations", n))
+	}
+}
@@ -0,0 +1,40 @@
+package unixcycle
+
+// Tracer starts a span for a lifecycle operation ("setup", "start", "ready" or "close") of a component,
+// returning the function ending the span with the error of the operation, nil on success.
+// It decouples the manager from any tracing library, see WithTracer
+type Tracer func(operation string, component string) (end func(err error))
+
+// WithTracer wraps the setup, start, readiness wait and close of every component in a span,
+// making startup and shutdown latency visible in traces. E.g. for OpenTelemetry:
+//
+//	tracer := otel.Tracer("unixcycle")
+//	unixcycle.WithTracer(func(operation, component string) func(error) {
+//		_, span := tracer.Start(ctx, operation+" "+component)
+//		return func(err error) {
+//			if err != nil {
+//				span.RecordError(err)
+//				span.SetStatus(codes.Error, err.Error())
+//			}
+//			span.End()
+//		}
+//	})
+//
+// Default is no tracing
+func WithTracer(tracer Tracer) managerOption {
+	return func(o *managerOptions) {
+		o.tracer = tracer
+	}
+}
+
+// traced runs f within a span of the tracer, if there is one
+func (m *Manager) traced(operation string, s namedComponent, f func() error) error {
+	if m.tracer == nil {
+		return f()
+	}
+
+	end := m.tracer(operation, s.name)
+	err := f()
+	end(err)
+	return err
+}
@@ -0,0 +1,47 @@
+package unixcycle_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestWithTracer(t *testing.T) {
+	t.Run("should wrap setup, start and close of components in spans", func(t *testing.T) {
+		var (
+			mu     sync.Mutex
+			spans  []string
+			tracer = func(operation, component string) func(error) {
+				return func(err error) {
+					mu.Lock()
+					defer mu.Unlock()
+					spans = append(spans, operation+" "+component+" "+errString(err))
+				}
+			}
+			sut = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithTracer(tracer),
+			).
+				Add("db", unixcycle.Setup(func() error { return nil })).
+				Add("cache", unixcycle.Closer(func() error { return assert.AnError }))
+		)
+
+		sut.Run()
+
+		assert.Equal(t, []string{
+			"setup db ok",
+			"start db ok",
+			"start cache ok",
+			"close cache " + assert.AnError.Error(),
+		}, spans)
+	})
+}
+
+func errString(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	return err.Error()
+}
@@ -0,0 +1,17 @@
+module github.com/theonewiththewrench/unixcycle/unixcyclelogrus
+
+go 1.23.0
+
+replace github.com/theonewiththewrench/unixcycle => ../
+
+require (
+	github.com/sirupsen/logrus v1.10.2
+	github.com/stretchr/testify v1.12.1
+	github.com/theonewiththewrench/unixcycle v0.0.0-00010101000000-000000000000
+)
+
+require (
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+	golang.org/x/sync v0.14.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+)
@@ -0,0 +1,32 @@
+// Package unixcyclelogrus passes the logs of a unixcycle.Manager to logrus, in its own module so unixcycle doesn't depend on logrus:
+//
+//	manager := unixcycle.NewManager(unixcycle.WithLogger(slog.New(unixcyclelogrus.Handler(logrus.StandardLogger()))))
+package unixcyclelogrus
+
+import (
+	"log/slog"
+
+	"github.com/sirupsen/logrus"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+// Logger adapts logger to a unixcycle.Logger, logging attributes as fields
+func Logger(logger logrus.FieldLogger) unixcycle.Logger {
+	level := func(log func(entry *logrus.Entry, msg string)) func(msg string, keysAndValues ...any) {
+		return func(msg string, keysAndValues ...any) {
+			log(logger.WithFields(unixcycle.KeyValues(keysAndValues)), msg)
+		}
+	}
+
+	return unixcycle.LoggerFuncs{
+		DebugFunc: level(func(entry *logrus.Entry, msg string) { entry.Debug(msg) }),
+		InfoFunc:  level(func(entry *logrus.Entry, msg string) { entry.Info(msg) }),
+		WarnFunc:  level(func(entry *logrus.Entry, msg string) { entry.Warn(msg) }),
+		ErrorFunc: level(func(entry *logrus.Entry, msg string) { entry.Error(msg) }),
+	}
+}
+
+// Handler adapts logger to a slog.Handler, to be used with unixcycle.WithLogger. Level filtering is left to logger
+func Handler(logger logrus.FieldLogger) slog.Handler {
+	return unixcycle.LoggerHandler(Logger(logger))
+}
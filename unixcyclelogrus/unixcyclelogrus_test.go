@@ -0,0 +1,39 @@
+package unixcyclelogrus_test
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/theonewiththewrench/unixcycle"
+	"github.com/theonewiththewrench/unixcycle/unixcyclelogrus"
+)
+
+func TestHandler(t *testing.T) {
+	t.Run("should get the manager's logs into logrus with their fields", func(t *testing.T) {
+		var (
+			logger, hook = test.NewNullLogger()
+			sut          = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithLogger(slog.New(unixcyclelogrus.Handler(logger))),
+			).Add("db", unixcycle.Closer(func() error { return nil }))
+		)
+		logger.SetOutput(io.Discard)
+
+		sut.Run()
+
+		var closing []*logrus.Entry
+		for _, entry := range hook.AllEntries() {
+			if entry.Message == `[UnixCycle] Closing component "db"` {
+				closing = append(closing, entry)
+			}
+		}
+		if assert.Len(t, closing, 1) {
+			assert.Equal(t, logrus.InfoLevel, closing[0].Level)
+			assert.Equal(t, "db", closing[0].Data["component_name"])
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package unixcycletest
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+var _ unixcycle.Component = &SpyComponent{}
+
+// callSeq orders the calls of all spies, so the order across components can be asserted
+var callSeq atomic.Uint64
+
+// Call is a recorded call of a method of a SpyComponent
+type Call struct {
+	Method string // "Setup", "Start" or "Close"
+	// Seq orders the calls across all spies, starting at 1
+	Seq  uint64
+	Time time.Time
+}
+
+// Behavior is what a method of a SpyComponent does when it is called
+type Behavior struct {
+	err   error
+	sleep time.Duration
+	block bool
+	panic any
+}
+
+// Succeed makes the method return nil right away
+func Succeed() Behavior {
+	return Behavior{}
+}
+
+// Fail makes the method return err
+func Fail(err error) Behavior {
+	return Behavior{err: err}
+}
+
+// Sleep makes the method return nil after d
+func Sleep(d time.Duration) Behavior {
+	return Behavior{sleep: d}
+}
+
+// Block makes the method block until SpyComponent.Release is called. Setup and Start are also unblocked by Close,
+// so a blocking Close can test close timeouts
+func Block() Behavior {
+	return Behavior{block: true}
+}
+
+// Panic makes the method panic with value
+func Panic(value any) Behavior {
+	return Behavior{panic: value}
+}
+
+// SpyComponent is a component recording its calls, with a configurable Behavior per method.
+// By default Setup and Close succeed and Start blocks until Close is called, like a server
+type SpyComponent struct {
+	setup, start, close Behavior
+
+	closed      chan struct{}
+	closeOnce   sync.Once
+	released    chan struct{}
+	releaseOnce sync.Once
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+func NewSpy() *SpyComponent {
+	return &SpyComponent{
+		start:    Block(),
+		closed:   make(chan struct{}),
+		released: make(chan struct{}),
+	}
+}
+
+// OnSetup sets what Setup does
+func (s *SpyComponent) OnSetup(behavior Behavior) *SpyComponent {
+	s.setup = behavior
+	return s
+}
+
+// OnStart sets what Start does
+func (s *SpyComponent) OnStart(behavior Behavior) *SpyComponent {
+	s.start = behavior
+	return s
+}
+
+// OnClose sets what Close does
+func (s *SpyComponent) OnClose(behavior Behavior) *SpyComponent {
+	s.close = behavior
+	return s
+}
+
+func (s *SpyComponent) Setup() error {
+	return s.call("Setup", s.setup, s.closed)
+}
+
+func (s *SpyComponent) Start() error {
+	return s.call("Start", s.start, s.closed)
+}
+
+func (s *SpyComponent) Close() error {
+	s.closeOnce.Do(func() { close(s.closed) })
+	return s.call("Close", s.close, nil)
+}
+
+// Release unblocks all calls blocked by Block, now and in the future
+func (s *SpyComponent) Release() {
+	s.releaseOnce.Do(func() { close(s.released) })
+}
+
+// Calls returns the calls recorded so far, in the order they were made
+func (s *SpyComponent) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.calls)
+}
+
+// Methods returns the methods called so far, in the order they were called
+func (s *SpyComponent) Methods() []string {
+	var methods []string
+	for _, call := range s.Calls() {
+		methods = append(methods, call.Method)
+	}
+	return methods
+}
+
+// Count returns how often method was called
+func (s *SpyComponent) Count(method string) int {
+	count := 0
+	for _, call := range s.Calls() {
+		if call.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+// call records the call of method and behaves like behavior. A nil unblock only unblocks on Release
+func (s *SpyComponent) call(method string, behavior Behavior, unblock <-chan struct{}) error {
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{Method: method, Seq: callSeq.Add(1), Time: time.Now()})
+	s.mu.Unlock()
+
+	if behavior.panic != nil {
+		panic(behavior.panic)
+	}
+	if behavior.sleep > 0 {
+		time.Sleep(behavior.sleep)
+	}
+	if behavior.block {
+		select {
+		case <-s.released:
+		case <-unblock:
+		}
+	}
+	return behavior.err
+}
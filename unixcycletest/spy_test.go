@@ -0,0 +1,67 @@
+package unixcycletest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theonewiththewrench/unixcycle"
+	"github.com/theonewiththewrench/unixcycle/unixcycletest"
+)
+
+func TestSpyComponent(t *testing.T) {
+	t.Run("should record its calls across components", func(t *testing.T) {
+		var (
+			lifetime = unixcycletest.NewLifetime()
+			db       = unixcycletest.NewSpy()
+			api      = unixcycletest.NewSpy()
+			sut      = unixcycle.NewManager(unixcycle.WithLifetime(lifetime.Wait))
+		)
+		sut.Add("db", db).Add("api", api)
+
+		unixcycletest.Start(t, sut, lifetime)
+		assert.Eventually(t, func() bool { return db.Count("Start") == 1 && api.Count("Start") == 1 }, time.Second, time.Millisecond)
+		got := unixcycletest.Shutdown(t, sut, lifetime, 0)
+
+		assert.NoError(t, got.Err)
+		assert.Equal(t, []string{"Setup", "Start", "Close"}, db.Methods())
+		assert.Equal(t, 1, api.Count("Close"))
+		assert.Less(t, api.Calls()[2].Seq, db.Calls()[2].Seq, "api should be closed before db")
+	})
+
+	t.Run("should behave as configured", func(t *testing.T) {
+		var (
+			failing  = unixcycletest.NewSpy().OnSetup(unixcycletest.Fail(assert.AnError))
+			sleeping = unixcycletest.NewSpy().OnClose(unixcycletest.Sleep(20 * time.Millisecond))
+			panics   = unixcycletest.NewSpy().OnStart(unixcycletest.Panic("boom"))
+			blocking = unixcycletest.NewSpy().OnClose(unixcycletest.Block())
+		)
+
+		assert.ErrorIs(t, failing.Setup(), assert.AnError)
+		began := time.Now()
+		assert.NoError(t, sleeping.Close())
+		assert.GreaterOrEqual(t, time.Since(began), 20*time.Millisecond)
+		assert.PanicsWithValue(t, "boom", func() { _ = panics.Start() })
+
+		closed := make(chan error)
+		go func() { closed <- blocking.Close() }()
+		select {
+		case <-closed:
+			t.Fatal("close should block until released")
+		case <-time.After(20 * time.Millisecond):
+		}
+		blocking.Release()
+		assert.NoError(t, <-closed)
+	})
+
+	t.Run("should unblock Start on Close", func(t *testing.T) {
+		var (
+			sut     = unixcycletest.NewSpy().OnStart(unixcycletest.Block())
+			started = make(chan error)
+		)
+		go func() { started <- sut.Start() }()
+
+		assert.NoError(t, sut.Close())
+		assert.NoError(t, <-started)
+	})
+}
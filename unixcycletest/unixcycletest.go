@@ -0,0 +1,170 @@
+// Package unixcycletest drives a unixcycle.Manager through its lifecycle in tests, deterministically:
+//
+//	var (
+//		lifetime = unixcycletest.NewLifetime()
+//		recorder = unixcycletest.NewRecorder()
+//		manager  = unixcycle.NewManager(unixcycle.WithLifetime(lifetime.Wait), unixcycle.WithEventHandler(recorder.Record))
+//	)
+//	manager.Add("db", db).Add("api", api)
+//
+//	unixcycletest.Start(t, manager, lifetime)
+//	result := unixcycletest.Shutdown(t, manager, lifetime, 0)
+//
+//	unixcycletest.RequireClosedInOrder(t, recorder, "api", "db")
+package unixcycletest
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+// StartTimeout is how long Start waits for all components to be started
+var StartTimeout = 5 * time.Second
+
+// Lifetime is a manager lifetime that ends when the test says so, see End
+type Lifetime struct {
+	signal chan int
+	once   sync.Once
+	ended  atomic.Bool
+}
+
+func NewLifetime() *Lifetime {
+	return &Lifetime{signal: make(chan int, 1)}
+}
+
+// Wait blocks until End is called, returning its signal. Pass it to unixcycle.WithLifetime
+func (l *Lifetime) Wait() int {
+	return <-l.signal
+}
+
+// End makes the manager shut down with signal. Only the first call has an effect
+func (l *Lifetime) End(signal int) {
+	l.once.Do(func() {
+		l.ended.Store(true)
+		l.signal <- signal
+	})
+}
+
+// Start starts manager and waits for all of its components to be started, failing the test if it doesn't.
+// Should the test end before Shutdown, the manager is shut down on cleanup
+func Start(t testing.TB, manager *unixcycle.Manager, lifetime *Lifetime) {
+	t.Helper()
+
+	if err := manager.Start(); err != nil {
+		t.Fatalf("starting manager: %v", err)
+	}
+	t.Cleanup(func() {
+		if !lifetime.ended.Load() {
+			lifetime.End(0)
+			manager.Wait()
+		}
+	})
+
+	select {
+	case <-manager.Started():
+	case <-time.After(StartTimeout):
+		t.Fatalf("components were not started within %s", StartTimeout)
+	}
+}
+
+// Shutdown ends lifetime with signal and waits for manager to shut down, returning the Result
+func Shutdown(t testing.TB, manager *unixcycle.Manager, lifetime *Lifetime, signal int) unixcycle.Result {
+	t.Helper()
+
+	lifetime.End(signal)
+	return manager.Wait()
+}
+
+// Recorder records the lifecycle events of a manager. Pass its Record method to unixcycle.WithEventHandler
+type Recorder struct {
+	mu     sync.Mutex
+	events []unixcycle.Event
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Record(event unixcycle.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+// Events returns the events recorded so far, in the order they were emitted
+func (r *Recorder) Events() []unixcycle.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.events)
+}
+
+// Components returns the names of the components in the order they emitted an event of kind
+func (r *Recorder) Components(kind unixcycle.EventKind) []string {
+	var names []string
+	for _, event := range r.Events() {
+		if event.Kind == kind && event.Component != "" {
+			names = append(names, event.Component)
+		}
+	}
+	return names
+}
+
+// RequireSetUpInOrder fails the test unless the named components were all set up, in this order.
+// Other components may be set up in between
+func RequireSetUpInOrder(t testing.TB, recorder *Recorder, names ...string) {
+	t.Helper()
+	requireInOrder(t, recorder, unixcycle.EventSetUp, "set up", names)
+}
+
+// RequireStartedInOrder fails the test unless the named components were all started, in this order.
+// Other components may be started in between
+func RequireStartedInOrder(t testing.TB, recorder *Recorder, names ...string) {
+	t.Helper()
+	requireInOrder(t, recorder, unixcycle.EventStarted, "started", names)
+}
+
+// RequireClosedInOrder fails the test unless the named components were all closed, in this order.
+// Other components may be closed in between
+func RequireClosedInOrder(t testing.TB, recorder *Recorder, names ...string) {
+	t.Helper()
+	requireInOrder(t, recorder, unixcycle.EventClosed, "closed", names)
+}
+
+func requireInOrder(t testing.TB, recorder *Recorder, kind unixcycle.EventKind, verb string, names []string) {
+	t.Helper()
+
+	var (
+		got  = recorder.Components(kind)
+		next = 0
+	)
+	for _, name := range got {
+		if next < len(names) && name == names[next] {
+			next++
+		}
+	}
+	if next < len(names) {
+		t.Fatalf("expected components %q to be %s in this order, but they were %s in order %q", names, verb, verb, got)
+	}
+}
+
+// RequireState fails the test unless the component named name is in state
+func RequireState(t testing.TB, manager *unixcycle.Manager, name string, state unixcycle.ComponentState) {
+	t.Helper()
+
+	for c := range manager.Components() {
+		if c.Name == name {
+			if c.State != state {
+				t.Fatalf("expected component %q to be %s, but it is %s", name, state, c.State)
+			}
+			return
+		}
+	}
+	t.Fatalf("unknown component %q", name)
+}
@@ -0,0 +1,104 @@
+package unixcycletest_test
+
+import (
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+	"github.com/theonewiththewrench/unixcycle/unixcycletest"
+)
+
+func TestLifecycle(t *testing.T) {
+	var (
+		lifetime = unixcycletest.NewLifetime()
+		recorder = unixcycletest.NewRecorder()
+		sut      = unixcycle.NewManager(unixcycle.WithLifetime(lifetime.Wait), unixcycle.WithEventHandler(recorder.Record))
+	)
+	sut.
+		Add("db", newServer()).
+		Add("cache", newServer()).
+		Add("api", newServer(), unixcycle.DependsOn("db"))
+
+	unixcycletest.Start(t, sut, lifetime) // Shuts the manager down at the end of the subtest otherwise
+
+	t.Run("should start the manager", func(t *testing.T) {
+		unixcycletest.RequireState(t, sut, "api", unixcycle.StateRunning)
+		unixcycletest.RequireStartedInOrder(t, recorder, "db", "api")
+	})
+
+	t.Run("should shut the manager down with the signal", func(t *testing.T) {
+		got := unixcycletest.Shutdown(t, sut, lifetime, int(syscall.SIGTERM))
+
+		assert.Equal(t, int(syscall.SIGTERM), got.Signal)
+		unixcycletest.RequireState(t, sut, "api", unixcycle.StateClosed)
+		unixcycletest.RequireClosedInOrder(t, recorder, "api", "db")
+		unixcycletest.RequireClosedInOrder(t, recorder, "cache", "db")
+	})
+
+	t.Run("should fail on components closed in another order", func(t *testing.T) {
+		fake := &fakeT{TB: t}
+
+		unixcycletest.RequireClosedInOrder(fake, recorder, "db", "api")
+
+		require.Len(t, fake.failures, 1)
+		assert.Contains(t, fake.failures[0], `expected components ["db" "api"] to be closed in this order`)
+	})
+
+	t.Run("should fail on components in another state", func(t *testing.T) {
+		fake := &fakeT{TB: t}
+
+		unixcycletest.RequireState(fake, sut, "api", unixcycle.StateRunning)
+		unixcycletest.RequireState(fake, sut, "nope", unixcycle.StateRunning)
+
+		assert.Equal(t, []string{`expected component "api" to be running, but it is closed`, `unknown component "nope"`}, fake.failures)
+	})
+}
+
+func TestStart(t *testing.T) {
+	t.Run("should shut the manager down on cleanup", func(t *testing.T) {
+		var (
+			lifetime = unixcycletest.NewLifetime()
+			closed   = false
+			sut      = unixcycle.NewManager(unixcycle.WithLifetime(lifetime.Wait))
+		)
+		sut.Add("closer", unixcycle.Closer(func() error { closed = true; return nil }))
+
+		t.Run("test without shutdown", func(t *testing.T) {
+			unixcycletest.Start(t, sut, lifetime)
+		})
+
+		assert.True(t, closed)
+	})
+}
+
+// server runs until closed
+type server struct {
+	stop chan struct{}
+}
+
+func newServer() *server {
+	return &server{stop: make(chan struct{})}
+}
+
+func (s *server) Start() error {
+	<-s.stop
+	return nil
+}
+
+func (s *server) Close() error {
+	close(s.stop)
+	return nil
+}
+
+// fakeT records failures instead of failing the test
+type fakeT struct {
+	testing.TB
+	failures []string
+}
+
+func (f *fakeT) Fatalf(format string, args ...any) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,20 @@
+module github.com/theonewiththewrench/unixcycle/unixcyclezap
+
+go 1.23.0
+
+replace github.com/theonewiththewrench/unixcycle => ../
+
+require (
+	github.com/stretchr/testify v1.10.0
+	github.com/theonewiththewrench/unixcycle v0.0.0-00010101000000-000000000000
+	go.uber.org/zap v1.28.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/sync v0.14.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,22 @@
+// Package unixcyclezap passes the logs of a unixcycle.Manager to zap, in its own module so unixcycle doesn't depend on zap:
+//
+//	manager := unixcycle.NewManager(unixcycle.WithLogger(slog.New(unixcyclezap.Handler(zapLogger))))
+package unixcyclezap
+
+import (
+	"log/slog"
+
+	"github.com/theonewiththewrench/unixcycle"
+	"go.uber.org/zap"
+)
+
+// Logger adapts logger to a unixcycle.Logger, logging attributes as the fields of its sugared logger
+func Logger(logger *zap.Logger) unixcycle.Logger {
+	sugar := logger.Sugar()
+	return unixcycle.LoggerFuncs{DebugFunc: sugar.Debugw, InfoFunc: sugar.Infow, WarnFunc: sugar.Warnw, ErrorFunc: sugar.Errorw}
+}
+
+// Handler adapts logger to a slog.Handler, to be used with unixcycle.WithLogger. Level filtering is left to logger
+func Handler(logger *zap.Logger) slog.Handler {
+	return unixcycle.LoggerHandler(Logger(logger))
+}
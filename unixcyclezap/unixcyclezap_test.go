@@ -0,0 +1,32 @@
+package unixcyclezap_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theonewiththewrench/unixcycle"
+	"github.com/theonewiththewrench/unixcycle/unixcyclezap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestHandler(t *testing.T) {
+	t.Run("should get the manager's logs into zap with their fields", func(t *testing.T) {
+		var (
+			core, logs = observer.New(zap.DebugLevel)
+			sut        = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return 0 }),
+				unixcycle.WithLogger(slog.New(unixcyclezap.Handler(zap.New(core)))),
+			).Add("db", unixcycle.Closer(func() error { return nil }))
+		)
+
+		sut.Run()
+
+		closing := logs.FilterMessage(`[UnixCycle] Closing component "db"`).All()
+		if assert.Len(t, closing, 1) {
+			assert.Equal(t, zap.InfoLevel, closing[0].Level)
+			assert.Equal(t, "db", closing[0].ContextMap()["component_name"])
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package unixcycle
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"strconv"
+)
+
+// Environment variables handing the listeners and a readiness pipe to the new process of an upgrade, see Manager.Upgrade
+const (
+	upgradeListenersEnv = "UNIXCYCLE_UPGRADE_LISTENERS"
+	upgradeReadyEnv     = "UNIXCYCLE_UPGRADE_READY_FD"
+)
+
+// WithUpgradeCommand sets the command starting the new process on Manager.Upgrade, e.g. to start a binary deployed
+// next to the running one. The listeners are handed over the same way as to the default command
+// Default is the running executable with the same arguments
+func WithUpgradeCommand(name string, args ...string) managerOption {
+	return func(o *managerOptions) {
+		o.upgradeCommand = append([]string{name}, args...)
+	}
+}
+
+// loadUpgradeListeners picks up the listeners handed over by the previous process of an upgrade, reporting whether there were any.
+// Called once, see loadedInheritedListeners
+func loadUpgradeListeners() bool {
+	encoded, readyFD := os.Getenv(upgradeListenersEnv), os.Getenv(upgradeReadyEnv)
+	_ = os.Unsetenv(upgradeListenersEnv)
+	_ = os.Unsetenv(upgradeReadyEnv)
+	if encoded == "" {
+		return false
+	}
+
+	inherited := &inheritedListeners
+	var keys []listenerKey
+	if err := json.Unmarshal([]byte(encoded), &keys); err != nil {
+		return false
+	}
+	for i, key := range keys {
+		f := os.NewFile(uintptr(listenFDsStart+i), key.Network+":"+key.Addr)
+		listener, err := net.FileListener(f)
+		f.Close()
+		if err == nil {
+			inherited.listeners[key] = listener
+		}
+	}
+	if fd, err := strconv.Atoi(readyFD); err == nil {
+		inherited.ready = os.NewFile(uintptr(fd), "upgrade ready")
+	}
+	return true
+}
+
+// upgradeReady tells the previous process that this one is ready, once all components are started, so it can shut down
+func upgradeReady() {
+	inherited := loadedInheritedListeners()
+	defer inherited.mu.Unlock()
+
+	if inherited.ready == nil {
+		return
+	}
+	_, _ = inherited.ready.Write([]byte{1})
+	inherited.ready.Close()
+	inherited.ready = nil
+}
@@ -0,0 +1,117 @@
+//go:build !windows
+
+package unixcycle_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+// upgradeChildEnv makes TestUpgradeChild act as the new process of TestUpgrade
+const upgradeChildEnv = "UNIXCYCLE_TEST_UPGRADE_CHILD"
+
+// upgradeAddr is requested by both processes, so the new one gets the port the old one was given
+const upgradeAddr = "127.0.0.1:0"
+
+func TestUpgrade(t *testing.T) {
+	t.Run("should hand the listeners to the new process and shut down once it is ready", func(t *testing.T) {
+		t.Setenv(upgradeChildEnv, "1")
+		var (
+			sut = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { select {} }),
+				unixcycle.WithUpgradeCommand(os.Args[0], "-test.run=^TestUpgradeChild$"),
+			)
+			listener = unixcycle.Listener("tcp", upgradeAddr, serveText("old"))
+			done     = make(chan int)
+		)
+		sut.Add("server", listener)
+		go func() { done <- sut.Run() }()
+		<-sut.Started()
+		assert.Equal(t, "old", get(t, listener.Addr()))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		require.NoError(t, sut.Upgrade(ctx))
+
+		assert.Equal(t, 0, <-done)
+		assert.Equal(t, "new", get(t, listener.Addr()), "the new process should serve on the same port")
+	})
+
+	t.Run("should keep running when the new process fails", func(t *testing.T) {
+		var (
+			shutdown = make(chan int, 1)
+			sut      = unixcycle.NewManager(
+				unixcycle.WithLifetime(func() int { return <-shutdown }),
+				unixcycle.WithUpgradeCommand("false"),
+			)
+			listener = unixcycle.Listener("tcp", upgradeAddr, serveText("old"))
+			done     = make(chan int)
+		)
+		sut.Add("server", listener)
+		go func() { done <- sut.Run() }()
+		<-sut.Started()
+
+		err := sut.Upgrade(context.Background())
+
+		assert.ErrorContains(t, err, "exited before it was ready")
+		assert.Equal(t, "old", get(t, listener.Addr()))
+		shutdown <- 0
+		<-done
+	})
+}
+
+// TestUpgradeChild is the new process started by TestUpgrade. It serves a single request and exits
+func TestUpgradeChild(t *testing.T) {
+	if os.Getenv(upgradeChildEnv) == "" {
+		t.Skip("only run as the new process of TestUpgrade")
+	}
+
+	served := make(chan struct{})
+	sut := unixcycle.NewManager(unixcycle.WithLifetime(func() int {
+		select {
+		case <-served:
+		case <-time.After(10 * time.Second):
+		}
+		return 0
+	}))
+	sut.Add("server", unixcycle.Listener("tcp", upgradeAddr, func(listener net.Listener) error {
+		return http.Serve(listener, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "new")
+			w.(http.Flusher).Flush()
+			close(served)
+		}))
+	}))
+	sut.Run()
+
+	os.Exit(0) // Don't print the test summary into the output of TestUpgrade
+}
+
+func serveText(text string) func(listener net.Listener) error {
+	return func(listener net.Listener) error {
+		return http.Serve(listener, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Connection", "close")
+			_, _ = io.WriteString(w, text)
+		}))
+	}
+}
+
+func get(t *testing.T, addr string) string {
+	t.Helper()
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}, Timeout: 5 * time.Second}
+	response, err := client.Get("http://" + addr)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	return string(body)
+}
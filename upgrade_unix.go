@@ -0,0 +1,178 @@
+//go:build !windows
+
+package unixcycle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// Upgrade replaces the process with a new one without dropping connections, for graceful binary upgrades on deploys.
+// It starts the new process (see WithUpgradeCommand), handing it the listeners of all components serving on one
+// (Listener, HTTPServer, GRPCServer, DebugServer, AdminSocket and those implementing Listeners() []net.Listener,
+// see Listen), and waits for it to start all of its components. Meanwhile both processes accept connections.
+// Once the new process is ready, the manager shuts down like its lifetime returned 0, and Upgrade returns nil.
+// When the new process fails to start or ctx is done first, the new process is killed and the manager keeps running.
+// Trigger it e.g. with manager.OnSignal(syscall.SIGUSR2, ...). With WithSystemdNotify, systemd is told about the new
+// main process, which needs NotifyAccess=all in the unit
+func (m *Manager) Upgrade(ctx context.Context) error {
+	if !m.upgrading.CompareAndSwap(false, true) {
+		return errors.New("an upgrade is already in progress")
+	}
+
+	pid, err := m.upgrade(ctx)
+	if err != nil {
+		m.upgrading.Store(false)
+		m.logError(CodeUpgradeFailed, fmt.Sprintf("Failure upgrading: %v", err), "error", err)
+		return err
+	}
+
+	m.logInfo(CodeUpgradeReady, fmt.Sprintf("Process %d is ready, shutting down", pid), slog.Int("pid", pid))
+	if m.systemdNotify {
+		m.notifySystemd(fmt.Sprintf("MAINPID=%d", pid))
+	}
+	m.signal(0)
+	return nil
+}
+
+// upgrade starts the new process and waits for it to be ready, returning its pid
+func (m *Manager) upgrade(ctx context.Context) (int, error) {
+	keys, listeners, files, err := m.listenerFiles()
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	if err != nil {
+		return 0, err
+	}
+	encodedKeys, err := json.Marshal(keys)
+	if err != nil {
+		return 0, err
+	}
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return 0, err
+	}
+	defer readyR.Close()
+
+	cmd, err := m.newUpgradeCommand()
+	if err != nil {
+		readyW.Close()
+		return 0, err
+	}
+	cmd.Env = append(cmd.Env,
+		upgradeListenersEnv+"="+string(encodedKeys),
+		fmt.Sprintf("%s=%d", upgradeReadyEnv, listenFDsStart+len(files)),
+	)
+	cmd.ExtraFiles = append(files, readyW)
+	err = cmd.Start()
+	readyW.Close() // Only the new process writes to it, so reading it fails once it exits
+	restoreNonblocking(files)
+	if err != nil {
+		return 0, fmt.Errorf("starting new process: %w", err)
+	}
+	m.logInfo(CodeUpgradeBegin, fmt.Sprintf("Upgrading to process %d", cmd.Process.Pid), slog.Int("pid", cmd.Process.Pid))
+	go func() { _ = cmd.Wait() }() // Reap it, should it exit while we are still around
+
+	ready := make(chan error, 1)
+	go func() {
+		_, err := readyR.Read(make([]byte, 1))
+		ready <- err
+	}()
+	select {
+	case err := <-ready:
+		if err != nil {
+			return 0, fmt.Errorf("process %d exited before it was ready", cmd.Process.Pid)
+		}
+	case <-ctx.Done():
+		_ = cmd.Process.Kill()
+		return 0, fmt.Errorf("waiting for process %d to be ready: %w", cmd.Process.Pid, ctx.Err())
+	}
+
+	// Closing our listeners must not remove the socket files the new process now serves on
+	for _, listener := range listeners {
+		if unixListener, ok := listener.(*net.UnixListener); ok {
+			unixListener.SetUnlinkOnClose(false)
+		}
+	}
+	return cmd.Process.Pid, nil
+}
+
+// listenerFiles duplicates the file descriptors of the listeners of all components serving on one
+func (m *Manager) listenerFiles() (keys []listenerKey, listeners []net.Listener, files []*os.File, err error) {
+	for _, s := range m.components {
+		var owned []net.Listener
+		var ownedKeys []listenerKey
+		switch owner := s.Component.(type) {
+		case interface {
+			handover() (listenerKey, net.Listener)
+		}:
+			key, listener := owner.handover()
+			if listener == nil {
+				continue // Not set up
+			}
+			owned, ownedKeys = []net.Listener{listener}, []listenerKey{key}
+		case listenerExporter:
+			owned = owner.Listeners()
+			for _, listener := range owned {
+				ownedKeys = append(ownedKeys, exportKey(listener))
+			}
+		}
+
+		for i, listener := range owned {
+			filer, ok := listener.(interface{ File() (*os.File, error) })
+			if !ok {
+				return keys, listeners, files, fmt.Errorf("listener of component %q can't be handed over", s.name)
+			}
+			f, err := filer.File()
+			if err != nil {
+				return keys, listeners, files, fmt.Errorf("handing over listener of component %q: %w", s.name, err)
+			}
+			keys, listeners, files = append(keys, ownedKeys[i]), append(listeners, listener), append(files, f)
+		}
+	}
+	return keys, listeners, files, nil
+}
+
+// restoreNonblocking puts the listeners back into non-blocking mode after starting a process with their file descriptors.
+// Passing them on puts them into blocking mode, and the duplicates share that mode with our listeners,
+// which then can't be closed while accepting
+func restoreNonblocking(files []*os.File) {
+	for _, f := range files {
+		if conn, err := f.SyscallConn(); err == nil {
+			_ = conn.Control(func(fd uintptr) { _ = syscall.SetNonblock(int(fd), true) })
+		}
+	}
+}
+
+// newUpgradeCommand returns the command starting the new process, with the environment of this one
+// but without the variables of a previous upgrade
+func (m *Manager) newUpgradeCommand() (*exec.Cmd, error) {
+	command := m.upgradeCommand
+	if len(command) == 0 {
+		executable, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("finding executable: %w", err)
+		}
+		command = append([]string{executable}, os.Args[1:]...)
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	for _, env := range os.Environ() {
+		if !strings.HasPrefix(env, upgradeListenersEnv+"=") && !strings.HasPrefix(env, upgradeReadyEnv+"=") {
+			cmd.Env = append(cmd.Env, env)
+		}
+	}
+	return cmd, nil
+}
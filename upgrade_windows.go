@@ -0,0 +1,13 @@
+//go:build windows
+
+package unixcycle
+
+import (
+	"context"
+	"errors"
+)
+
+// Upgrade is not supported on Windows, which can't hand listeners to a new process
+func (m *Manager) Upgrade(ctx context.Context) error {
+	return errors.New("upgrades are not supported on windows")
+}
@@ -0,0 +1,92 @@
+package unixcycle
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Validate checks the components and options of the manager without running anything:
+// duplicate or empty names, nil components, unknown or cyclic dependencies and conflicting options.
+// All problems found are returned joined together. Useful in CI to catch wiring mistakes before deploying
+func (m *Manager) Validate() error {
+	errs := slices.Clone(m.addErrs)
+	for _, s := range m.components {
+		if s.options.setupTimeout < 0 || s.options.closeTimeout < 0 {
+			errs = append(errs, fmt.Errorf("component %q has a negative timeout", s.name))
+		}
+	}
+
+	if _, err := orderByDependencies(m.components); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.checkGroups(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(append(errs, m.checkOptions()...)...)
+}
+
+// checkAdd returns why a component can not be added under name, if it can't
+func (m *Manager) checkAdd(name string, component Component) error {
+	if m.runState.Load() != managerIdle {
+		return fmt.Errorf("%w: component %q", ErrAddAfterStart, name)
+	}
+	if name == "" {
+		return errors.New("component without a name")
+	}
+	if slices.ContainsFunc(m.components, func(s namedComponent) bool { return s.name == name }) {
+		return fmt.Errorf("duplicate component name %q", name)
+	}
+	if isNil(component) {
+		return fmt.Errorf("component %q is nil", name)
+	}
+	return nil
+}
+
+// checkOptions returns the options that conflict with each other or can never work
+func (m *Manager) checkOptions() []error {
+	var errs []error
+	for _, timeout := range []struct {
+		name     string
+		duration time.Duration
+	}{{"setup", m.setupTimeout}, {"close", m.closeTimeout}, {"checkpoint", m.checkpointTimeout}, {"drain", m.drainTimeout}} {
+		if timeout.duration <= 0 {
+			errs = append(errs, fmt.Errorf("%s timeout must be positive", timeout.name))
+		}
+	}
+	if m.shutdownBudget < 0 || m.shutdownDelay < 0 || m.closeGap < 0 {
+		errs = append(errs, errors.New("shutdown budget, shutdown delay and close gap can not be negative"))
+	}
+	if m.shutdownBudget > 0 && m.shutdownDelay >= m.shutdownBudget {
+		errs = append(errs, fmt.Errorf("shutdown delay %s leaves nothing of the shutdown budget %s", m.shutdownDelay, m.shutdownBudget))
+	}
+	if m.shutdownBudget > 0 && m.closeGap*time.Duration(len(m.components)) >= m.shutdownBudget {
+		errs = append(errs, fmt.Errorf("close gap %s between %d components does not fit within the shutdown budget %s", m.closeGap, len(m.components), m.shutdownBudget))
+	}
+	for i, group := range m.startupGroups {
+		if slices.Index(m.startupGroups, group) != i {
+			errs = append(errs, fmt.Errorf("duplicate startup group %q", group))
+		}
+	}
+	if m.lifetime == nil {
+		errs = append(errs, errors.New("lifetime can not be nil"))
+	}
+
+	return errs
+}
+
+// isNil reports whether c is nil, including a nil pointer wrapped in the Component interface
+func isNil(c Component) bool {
+	if c == nil {
+		return true
+	}
+	v := reflect.ValueOf(c)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
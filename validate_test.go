@@ -0,0 +1,50 @@
+package unixcycle_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theonewiththewrench/unixcycle"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("should accept a correctly wired manager", func(t *testing.T) {
+		sut := unixcycle.NewManager().
+			Add("db", unixcycle.Setup(func() error { return nil })).
+			Add("api", unixcycle.Starter(func() error { return nil }), unixcycle.DependsOn("db"))
+
+		assert.NoError(t, sut.Validate())
+	})
+
+	t.Run("should report every wiring mistake", func(t *testing.T) {
+		var (
+			nilComponent *testComponent
+			sut          = unixcycle.NewManager(
+				unixcycle.WithCloseTimeout(0),
+			).
+				Add("db", unixcycle.Setup(func() error { return nil })).
+				Add("db", unixcycle.Setup(func() error { return nil })).
+				Add("broken", nilComponent).
+				Add("api", unixcycle.Starter(func() error { return nil }), unixcycle.DependsOn("cache")).
+				Add("server", unixcycle.Starter(func() error { return nil }), unixcycle.WithComponentSetupTimeout(-time.Second))
+		)
+
+		err := sut.Validate()
+
+		assert.ErrorContains(t, err, `duplicate component name "db"`)
+		assert.ErrorContains(t, err, `component "broken" is nil`)
+		assert.ErrorContains(t, err, `component "api" depends on unknown component "cache"`)
+		assert.ErrorContains(t, err, `component "server" has a negative timeout`)
+		assert.ErrorContains(t, err, "close timeout must be positive")
+	})
+
+	t.Run("should accept supervision without a backoff, which restarts after a second", func(t *testing.T) {
+		sut := unixcycle.NewManager(unixcycle.WithSupervision(unixcycle.OneForOne, nil, 3)).
+			Add("worker", unixcycle.Starter(func() error { return nil }))
+
+		err := sut.Validate()
+
+		assert.NoError(t, err)
+	})
+}